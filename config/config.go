@@ -10,4 +10,11 @@ const (
 	SkipRollback
 	RunningRollback
 	MigrationFileCreated
+	SkipNotApplicable
+	RunningSeed
+	SeededItems
+	// BaselineSavingObject, BaselineLoadingObject and BaselineRollingBackObject messages are "<index>/<total> <object type> <name>"
+	BaselineSavingObject
+	BaselineLoadingObject
+	BaselineRollingBackObject
 )
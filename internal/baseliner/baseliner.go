@@ -3,6 +3,9 @@ package baseliner
 
 import (
 	"database/sql"
+	"io/fs"
+
+	"github.com/olbrichattila/godbmigrator/internal/messager"
 )
 
 const (
@@ -13,40 +16,120 @@ const (
 	queryTypeProcedures    = "procedure"
 	queryTypeFunctions     = "function"
 	queryTypeTriggers      = "trigger"
+	queryTypeSequences     = "sequence"
+	queryTypeSchema        = "schema"
+	queryTypeConstraints   = "constraint"
+	queryTypeComments      = "comment"
+	queryTypeExtensions    = "extension"
+	queryTypeTypes         = "type"
+	queryTypeDomains       = "domain"
+	queryTypePolicies      = "policy"
+	queryTypeGrants        = "grant"
+	queryTypeData          = "data"
+
+	// baselineFileName is saved into the migration folder
+	baselineFileName = "baseline.sql"
+	// rollbackFileName is saved next to the baseline, it drops the objects of the baseline
+	rollbackFileName = "baseline-rollback.sql"
+	// versionAnnotation stamps the baseline with the last migration it covers
+	versionAnnotation = "-- migrator:baseline"
+	// objectAnnotation precedes every statement with the type and name of the object it creates
+	objectAnnotation = "-- migrator:object"
+	// maxLineSize is the longest line read from a baseline, every row of a data insert is a single line
+	maxLineSize = 1 << 30
 
 	// SQL file Delimiters
 	openingDelimiter = "DELIMITER ;"
 	closingDelimiter = "DELIMITER ;;"
 )
 
-// New baseliner, which saves and restores database structure
-func New(db *sql.DB) Baseliner {
+// FilterFunc tells if an object of the given type and name is part of the baseline
+type FilterFunc func(objectType, name string) bool
+
+// New baseliner, which saves and restores database structure of the given dialect
+// msg receives the progress of Save and Load, it can be nil
+// filter leaves objects out of saving, loading and drift detection, nil keeps every object
+// schemas are PostgreSQL schemas saved with schema qualified DDL, if empty the current schema is used
+func New(db *sql.DB, dialect string, msg messager.Messager, filter FilterFunc, schemas ...string) Baseliner {
 	return &baselilner{
-		db: db,
+		db:      db,
+		dialect: dialect,
+		msg:     msg,
+		filter:  filter,
+		schemas: schemas,
 	}
 }
 
-// Baseliner implements Save and Load
+// Baseliner implements Save and Load, Rollback drops the objects of a saved baseline
+// Version returns the last migration covered by a saved baseline
+// Drift compares the live database with the baseline or a snapshot saved by SaveSnapshot
 type Baseliner interface {
-	Save(migrationFilePath string) error
-	Load(migrationFilePath string) error
+	Save(migrationFilePath string, options SaveOptions) error
+	SaveSnapshot(fileName string) error
+	SaveTranslated(migrationFilePath, targetDialect string, options SaveOptions) ([]TranslationIssue, error)
+	Load(fsys fs.FS, options LoadOptions) error
+	Rollback(fsys fs.FS) error
+	Statements(fsys fs.FS) ([]Object, error)
+	Version(fsys fs.FS) (string, error)
+	Drift(fsys fs.FS, fileName string) ([]Drift, error)
+	Objects() ([]Object, error)
+	Inspect() (*Schema, error)
+	Columns(tableName string) ([]Column, error)
+}
+
+// schemaObject is a database object and the SQL creating it
+type schemaObject struct {
+	objectType string
+	name       string
+	sql        string
 }
 
 type retrievalInstruction struct {
-	query                string
+	query string
+	// bulkQuery returns the name and SQL of every object of the type in one query, the per object query is used if it is empty
+	bulkQuery            string
 	fieldPosition        int
 	dbNameShouldBePassed bool
 }
 
 type baselineInstruction struct {
 	execute                []string
+	createSchemaSQL        string
 	listerQueries          map[string]string
 	schemaRetrievalQueries map[string]retrievalInstruction
 	activeDatabaseSQL      string
+	// inspectQueries read the details of the tables for Inspect
+	inspectQueries inspectQueries
+	// columnsQuery returns name, type, not null, default, primary key and extra of the columns of a table
+	columnsQuery string
+	// dependencyQuery returns object type, name, then schema, type and name of the object it depends on, and if it is a foreign key
+	dependencyQuery string
+	// parseDependencies finds the dependencies in the SQL of the objects, when the database has no dependency catalog
+	parseDependencies bool
+	// deferForeignKey moves the foreign keys referencing a table out of the table SQL into separate constraints
+	deferForeignKey func(table schemaObject, referencedTable string) (schemaObject, []schemaObject)
+	// transactionalDDL tells if schema changes can be rolled back, so the baseline loads in one transaction
+	transactionalDDL bool
+	// parallelRetrieval is the number of concurrent per object queries, for types without a bulk query
+	parallelRetrieval int
+	// identifierQuote quotes the names of the rollback statements
+	identifierQuote string
+	// dropConstraintSQL drops a constraint, formatted with the table and the constraint name
+	dropConstraintSQL string
+	// dropTriggerOnTable tells if a trigger is dropped on its table, trigger names are then <table>.<name>
+	dropTriggerOnTable bool
+	// translation describes the dialect as the source and the target of a translated baseline
+	translation translationRules
+	// sequenceValuesQuery returns statements setting the sequences of the columns of a table to their current value
+	sequenceValuesQuery string
 }
 
 type baselilner struct {
 	baselineInstruction baselineInstruction
 	db                  *sql.DB
+	dialect             string
 	databaseName        string
+	msg                 messager.Messager
+	filter              FilterFunc
+	schemas             []string
 }
@@ -0,0 +1,251 @@
+package baseliner
+
+import (
+	"encoding/hex"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/olbrichattila/godbmigrator/internal/dbtypemanager"
+)
+
+// dataChunkSize is the number of rows inserted by one multi row insert
+const dataChunkSize = 100
+
+// matchingTables returns the tables matching the patterns, in the order they are created
+func matchingTables(tables, patterns []string) ([]string, error) {
+	var matching []string
+	for _, table := range tables {
+		matched, err := matchesAny(table, patterns)
+		if err != nil {
+			return nil, err
+		}
+
+		if matched {
+			matching = append(matching, table)
+		}
+	}
+
+	return matching, nil
+}
+
+func matchesAny(name string, patterns []string) (bool, error) {
+	for _, pattern := range patterns {
+		matched, err := path.Match(pattern, name)
+		if err != nil {
+			return false, fmt.Errorf("invalid table pattern %s, error: %v", pattern, err)
+		}
+
+		if matched {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// walkInserts calls back with multi row inserts of the rows of the table, followed by the sequence values if the dialect has them
+func (b *baselilner) walkInserts(table string, callback func(schemaObject) error) error {
+	quotedTable := b.quoteTableName(table)
+	rows, err := b.db.Query("SELECT * FROM " + quotedTable)
+	if err != nil {
+		return fmt.Errorf("cannot read data of table %s, error: %v", table, err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return fmt.Errorf("cannot get columns of table %s, error: %v", table, err)
+	}
+
+	quotedColumns := make([]string, len(columns))
+	for i, column := range columns {
+		quotedColumns[i] = b.quoteIdentifier(column)
+	}
+
+	insert := "INSERT INTO " + quotedTable + " (" + strings.Join(quotedColumns, ", ") + ")"
+	if b.dialect == dbtypemanager.DbTypePostgres {
+		// Identity columns generated always accept the saved values only this way
+		insert += " OVERRIDING SYSTEM VALUE"
+	}
+
+	flush := func(values []string) error {
+		return callback(schemaObject{
+			objectType: queryTypeData,
+			name:       table,
+			sql:        insert + " VALUES\n" + strings.Join(values, ",\n"),
+		})
+	}
+
+	values := make([]any, len(columns))
+	pointers := make([]any, len(columns))
+	for i := range pointers {
+		pointers[i] = &values[i]
+	}
+
+	var chunk []string
+	for rows.Next() {
+		err := rows.Scan(pointers...)
+		if err != nil {
+			return fmt.Errorf("cannot read row of table %s, error: %v", table, err)
+		}
+
+		literals := make([]string, len(values))
+		for i, value := range values {
+			literals[i] = b.sqlLiteral(value)
+		}
+
+		chunk = append(chunk, "("+strings.Join(literals, ", ")+")")
+		if len(chunk) == dataChunkSize {
+			err = flush(chunk)
+			if err != nil {
+				return err
+			}
+			chunk = nil
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("cannot read data of table %s, error: %v", table, err)
+	}
+
+	if len(chunk) > 0 {
+		err = flush(chunk)
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.walkSequenceValues(table, quotedTable, callback)
+}
+
+// walkSequenceValues calls back with statements moving the sequences of the table past the saved rows
+func (b *baselilner) walkSequenceValues(table, quotedTable string, callback func(schemaObject) error) error {
+	if b.baselineInstruction.sequenceValuesQuery == "" {
+		return nil
+	}
+
+	rows, err := b.db.Query(b.baselineInstruction.sequenceValuesQuery, quotedTable)
+	if err != nil {
+		return fmt.Errorf("cannot get sequence values of table %s, error: %v", table, err)
+	}
+	defer rows.Close()
+
+	var statements []string
+	for rows.Next() {
+		var statement string
+		err := rows.Scan(&statement)
+		if err != nil {
+			return fmt.Errorf("cannot get sequence value of table %s, error: %v", table, err)
+		}
+
+		statements = append(statements, statement)
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("cannot get sequence values of table %s, error: %v", table, err)
+	}
+
+	for _, statement := range statements {
+		err = callback(schemaObject{objectType: queryTypeData, name: table, sql: statement})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// quoteTableName quotes the table name, schema qualified names are quoted by part
+func (b *baselilner) quoteTableName(table string) string {
+	if len(b.schemas) > 0 {
+		if schema, name, ok := strings.Cut(table, "."); ok {
+			return b.quoteIdentifier(schema) + "." + b.quoteIdentifier(name)
+		}
+	}
+
+	return b.quoteIdentifier(table)
+}
+
+func (b *baselilner) quoteIdentifier(name string) string {
+	if b.dialect == dbtypemanager.DbTypeMySQL {
+		return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+	}
+
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// sqlLiteral formats a scanned value as a literal of the dialect
+func (b *baselilner) sqlLiteral(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "NULL"
+	case bool:
+		if b.dialect == dbtypemanager.DbTypeSqlite {
+			if v {
+				return "1"
+			}
+			return "0"
+		}
+		return strings.ToUpper(strconv.FormatBool(v))
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case time.Time:
+		if b.dialect == dbtypemanager.DbTypeMySQL {
+			return b.stringLiteral(v.Format("2006-01-02 15:04:05.999999"))
+		}
+		return b.stringLiteral(v.Format("2006-01-02 15:04:05.999999999-07:00"))
+	case []byte:
+		if utf8.Valid(v) {
+			return b.stringLiteral(string(v))
+		}
+		return b.binaryLiteral(v)
+	case string:
+		return b.stringLiteral(v)
+	default:
+		return b.stringLiteral(fmt.Sprint(v))
+	}
+}
+
+// stringLiteral quotes the value for the dialect, line breaks are escaped so every row of an insert stays on one line
+// The baseline reader splits statements by line, a line break in a literal would end or drop a part of it
+func (b *baselilner) stringLiteral(value string) string {
+	value = strings.ReplaceAll(value, "'", "''")
+	escaper := strings.NewReplacer(`\`, `\\`, "\r", `\r`, "\n", `\n`)
+
+	switch {
+	case b.dialect == dbtypemanager.DbTypeMySQL:
+		// MySQL treats backslash as an escape character by default
+		return "'" + escaper.Replace(value) + "'"
+	case !strings.ContainsAny(value, "\r\n"):
+		return "'" + value + "'"
+	case b.dialect == dbtypemanager.DbTypePostgres:
+		return "E'" + escaper.Replace(value) + "'"
+	case b.dialect == dbtypemanager.DbTypeDuckDB:
+		return concatLiteral(value, "chr")
+	default:
+		return concatLiteral(value, "char")
+	}
+}
+
+// concatLiteral concatenates the lines of the value with the line break characters returned by the character function
+func concatLiteral(value, charFunction string) string {
+	value = strings.NewReplacer("\r", "' || "+charFunction+"(13) || '", "\n", "' || "+charFunction+"(10) || '").Replace(value)
+	return "('" + value + "')"
+}
+
+func (b *baselilner) binaryLiteral(value []byte) string {
+	switch b.dialect {
+	case dbtypemanager.DbTypePostgres:
+		return `'\x` + hex.EncodeToString(value) + `'::bytea`
+	case dbtypemanager.DbTypeDuckDB:
+		return "from_hex('" + hex.EncodeToString(value) + "')"
+	default:
+		return "X'" + hex.EncodeToString(value) + "'"
+	}
+}
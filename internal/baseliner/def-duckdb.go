@@ -0,0 +1,66 @@
+package baseliner
+
+// getDuckDBInstruction lists tables by their foreign key depth, as DuckDB requires referenced tables to exist at creation time
+func (b *baselilner) getDuckDBInstruction() *baselineInstruction {
+	return &baselineInstruction{
+		execute: []string{queryTypeSequences, queryTypeTables, queryTypeIndex, queryTypeViews},
+		listerQueries: map[string]string{
+			queryTypeSequences: "SELECT sequence_name FROM duckdb_sequences() WHERE schema_name = $1 AND NOT temporary ORDER BY sequence_name",
+			queryTypeTables:    "WITH RECURSIVE fk(table_name, depth) AS (SELECT table_name, 0 FROM duckdb_tables() WHERE schema_name = $1 AND NOT internal AND NOT temporary UNION ALL SELECT c.table_name, fk.depth + 1 FROM duckdb_constraints() c JOIN fk ON c.referenced_table = fk.table_name WHERE c.constraint_type = 'FOREIGN KEY' AND c.schema_name = $1 AND c.table_name <> c.referenced_table) SELECT table_name FROM fk GROUP BY table_name ORDER BY max(depth), table_name",
+			queryTypeIndex:     "SELECT index_name FROM duckdb_indexes() WHERE schema_name = $1 AND sql IS NOT NULL ORDER BY table_name, index_name",
+			queryTypeViews:     "SELECT view_name FROM duckdb_views() WHERE schema_name = $1 AND NOT internal AND NOT temporary ORDER BY view_oid",
+		},
+		schemaRetrievalQueries: map[string]retrievalInstruction{
+			queryTypeSequences: {
+				query:                "SELECT rtrim(sql, ';') FROM duckdb_sequences() WHERE schema_name = '%s' AND sequence_name = '%s'",
+				bulkQuery:            "SELECT sequence_name, rtrim(sql, ';') FROM duckdb_sequences() WHERE schema_name = '%s'",
+				dbNameShouldBePassed: true,
+			},
+			queryTypeTables: {
+				query:                "SELECT rtrim(sql, ';') FROM duckdb_tables() WHERE schema_name = '%s' AND table_name = '%s'",
+				bulkQuery:            "SELECT table_name, rtrim(sql, ';') FROM duckdb_tables() WHERE schema_name = '%s'",
+				dbNameShouldBePassed: true,
+			},
+			queryTypeIndex: {
+				query:                "SELECT rtrim(sql, ';') FROM duckdb_indexes() WHERE schema_name = '%s' AND index_name = '%s'",
+				bulkQuery:            "SELECT index_name, rtrim(sql, ';') FROM duckdb_indexes() WHERE schema_name = '%s'",
+				dbNameShouldBePassed: true,
+			},
+			queryTypeViews: {
+				query:                "SELECT rtrim(sql, ';') FROM duckdb_views() WHERE schema_name = '%s' AND view_name = '%s'",
+				bulkQuery:            "SELECT view_name, rtrim(sql, ';') FROM duckdb_views() WHERE schema_name = '%s'",
+				dbNameShouldBePassed: true,
+			},
+		},
+		inspectQueries: inspectQueries{
+			columns: "SELECT c.table_name, c.column_name, c.data_type, NOT c.is_nullable, c.column_default, " +
+				"EXISTS (SELECT 1 FROM duckdb_constraints() k WHERE k.schema_name = c.schema_name AND k.table_name = c.table_name " +
+				"AND k.constraint_type = 'PRIMARY KEY' AND list_contains(k.constraint_column_names, c.column_name)), '', c.comment " +
+				"FROM duckdb_columns() c WHERE c.schema_name = $1 AND NOT c.internal ORDER BY c.table_name, c.column_index",
+			tableComments: "SELECT table_name, comment FROM duckdb_tables() WHERE schema_name = $1 AND comment IS NOT NULL",
+			constraints: "SELECT table_name, constraint_key, constraint_name, constraint_type, column_name, schema_name, referenced_table, referenced_column, check_clause FROM (" +
+				"SELECT table_name, CAST(constraint_index AS VARCHAR) AS constraint_key, constraint_index, constraint_name, constraint_type, " +
+				"unnest(constraint_column_names) AS column_name, CASE WHEN referenced_table IS NOT NULL THEN schema_name END AS schema_name, referenced_table, " +
+				"unnest(referenced_column_names) AS referenced_column, CASE WHEN constraint_type = 'CHECK' THEN expression END AS check_clause, " +
+				"generate_subscripts(constraint_column_names, 1) AS position " +
+				"FROM duckdb_constraints() WHERE schema_name = $1 AND constraint_type IN ('PRIMARY KEY', 'UNIQUE', 'FOREIGN KEY', 'CHECK')" +
+				") ORDER BY table_name, constraint_index, position",
+			indexes:           "SELECT table_name, index_name, is_unique, expressions FROM duckdb_indexes() WHERE schema_name = $1 ORDER BY table_name, index_name",
+			splitIndexColumns: true,
+		},
+		activeDatabaseSQL: "SELECT current_schema()",
+		identifierQuote:   "\"",
+		translation: translationRules{
+			types: map[string]string{
+				typeInteger: "INTEGER", typeBigInt: "BIGINT", typeSmallInt: "SMALLINT", typeDecimal: "DECIMAL", typeReal: "REAL", typeDouble: "DOUBLE",
+				typeBoolean: "BOOLEAN", typeVarchar: "VARCHAR", typeChar: "VARCHAR", typeText: "VARCHAR", typeBlob: "BLOB", typeDate: "DATE", typeTime: "TIME",
+				typeTimestamp: "TIMESTAMP", typeTimestampTZ: "TIMESTAMPTZ", typeJSON: "JSON", typeUUID: "UUID",
+			},
+			sequenceDefault: true,
+			booleans:        [2]string{"FALSE", "TRUE"},
+			currentDate:     "CURRENT_DATE",
+			commentOn:       true,
+		},
+		transactionalDDL: true,
+	}
+}
@@ -4,24 +4,65 @@ func (b *baselilner) getSQLiteInstruction() *baselineInstruction {
 	return &baselineInstruction{
 		execute: []string{queryTypeTables, queryTypeIndex, queryTypeViews, queryTypeTriggers},
 		listerQueries: map[string]string{
-			queryTypeTables:   "SELECT name FROM sqlite_master WHERE type = \"table\"",
-			queryTypeIndex:    "SELECT name FROM sqlite_master WHERE type = \"index\"",
-			queryTypeViews:    "SELECT name FROM sqlite_master WHERE type = \"view\"",
-			queryTypeTriggers: "SELECT name FROM sqlite_master WHERE type = \"trigger\"",
+			queryTypeTables:   "SELECT name FROM sqlite_master WHERE type = \"table\" AND substr(name, 1, 7) <> 'sqlite_' ORDER BY name",
+			queryTypeIndex:    "SELECT name FROM sqlite_master WHERE type = \"index\" AND sql IS NOT NULL ORDER BY name",
+			queryTypeViews:    "SELECT name FROM sqlite_master WHERE type = \"view\" ORDER BY name",
+			queryTypeTriggers: "SELECT name FROM sqlite_master WHERE type = \"trigger\" ORDER BY name",
 		},
 		schemaRetrievalQueries: map[string]retrievalInstruction{
 			queryTypeTables: {
-				query: "SELECT sql FROM sqlite_master WHERE type = \"table\" and name = \"%s\"",
+				query:     "SELECT sql FROM sqlite_master WHERE type = \"table\" and name = \"%s\"",
+				bulkQuery: "SELECT name, sql FROM sqlite_master WHERE type = \"table\"",
 			},
 			queryTypeIndex: {
-				query: "SELECT sql FROM sqlite_master WHERE type = \"index\" and name = \"%s\"",
+				query:     "SELECT sql FROM sqlite_master WHERE type = \"index\" and name = \"%s\"",
+				bulkQuery: "SELECT name, sql FROM sqlite_master WHERE type = \"index\"",
 			},
 			queryTypeViews: {
-				query: "SELECT sql FROM sqlite_master WHERE type = \"view\" and name = \"%s\"",
+				query:     "SELECT sql FROM sqlite_master WHERE type = \"view\" and name = \"%s\"",
+				bulkQuery: "SELECT name, sql FROM sqlite_master WHERE type = \"view\"",
 			},
 			queryTypeTriggers: {
-				query: "SELECT sql FROM sqlite_master WHERE type = \"trigger\" and name = \"%s\"",
+				query:     "SELECT sql FROM sqlite_master WHERE type = \"trigger\" and name = \"%s\"",
+				bulkQuery: "SELECT name, sql FROM sqlite_master WHERE type = \"trigger\"",
 			},
 		},
+		inspectQueries: inspectQueries{
+			columns: "SELECT m.name, p.name, p.type, p.\"notnull\", p.dflt_value, p.pk > 0, '', NULL " +
+				"FROM sqlite_master m JOIN pragma_table_info(m.name) p WHERE m.type = 'table' ORDER BY m.name, p.cid",
+			// SQLite does not name its constraints and has no catalog of check constraints
+			constraints: "SELECT table_name, constraint_key, NULL, constraint_type, column_name, NULL, referenced_table, referenced_column, NULL FROM (" +
+				"SELECT m.name AS table_name, 'p' AS constraint_key, 'PRIMARY KEY' AS constraint_type, p.name AS column_name, " +
+				"NULL AS referenced_table, NULL AS referenced_column, p.pk AS position " +
+				"FROM sqlite_master m JOIN pragma_table_info(m.name) p WHERE m.type = 'table' AND p.pk > 0 " +
+				"UNION ALL " +
+				"SELECT m.name, 'u' || i.name, 'UNIQUE', c.name, NULL, NULL, c.seqno " +
+				"FROM sqlite_master m JOIN pragma_index_list(m.name) i JOIN pragma_index_info(i.name) c WHERE m.type = 'table' AND i.origin = 'u' " +
+				"UNION ALL " +
+				"SELECT m.name, 'f' || f.id, 'FOREIGN KEY', f.\"from\", f.\"table\", f.\"to\", f.seq " +
+				"FROM sqlite_master m JOIN pragma_foreign_key_list(m.name) f WHERE m.type = 'table'" +
+				") ORDER BY table_name, constraint_key, position",
+			indexes: "SELECT m.name, i.name, i.\"unique\", c.name FROM sqlite_master m JOIN pragma_index_list(m.name) i JOIN pragma_index_info(i.name) c " +
+				"WHERE m.type = 'table' AND i.origin = 'c' ORDER BY m.name, i.name, c.seqno",
+			triggers: "SELECT tbl_name, name FROM sqlite_master WHERE type = 'trigger'",
+		},
+		columnsQuery:      "SELECT name, type, \"notnull\", dflt_value, pk > 0, '' FROM pragma_table_info(?) ORDER BY cid",
+		parseDependencies: true,
+		identifierQuote:   "\"",
+		translation: translationRules{
+			types: map[string]string{
+				typeInteger: "INTEGER", typeBigInt: "INTEGER", typeSmallInt: "INTEGER", typeDecimal: "NUMERIC", typeReal: "REAL", typeDouble: "REAL",
+				typeBoolean: "BOOLEAN", typeVarchar: "VARCHAR", typeChar: "CHAR", typeText: "TEXT", typeBlob: "BLOB", typeDate: "DATE", typeTime: "TIME",
+				typeTimestamp: "DATETIME", typeTimestampTZ: "DATETIME", typeJSON: "TEXT", typeUUID: "TEXT",
+			},
+			autoIncrement:      "AUTOINCREMENT",
+			inlinePrimaryKey:   true,
+			booleans:           [2]string{"0", "1"},
+			currentDate:        "CURRENT_DATE",
+			forwardForeignKeys: true,
+			rowidPrimaryKey:    true,
+			typeAffinity:       true,
+		},
+		transactionalDDL: true,
 	}
 }
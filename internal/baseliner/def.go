@@ -8,11 +8,7 @@ import (
 
 // getEngineSpecificInstructions, if you implement a new database, please add it here with the corresponding dev-<my-database>.go
 func (b *baselilner) getEngineSpecificInstructions() (*baselineInstruction, error) {
-	dbType, err := dbtypemanager.GetDiverType(b.db)
-	if err != nil {
-		return nil, fmt.Errorf("getEngineSpecificInstructions: error %v", err)
-	}
-
+	dbType := b.dialect
 	var instructions *baselineInstruction
 
 	switch dbType {
@@ -24,6 +20,8 @@ func (b *baselilner) getEngineSpecificInstructions() (*baselineInstruction, erro
 		instructions = b.getPostgreSQLInstruction()
 	case dbtypemanager.DbTypeFirebird:
 		instructions = b.getFirebirdSQLInstruction()
+	case dbtypemanager.DbTypeDuckDB:
+		instructions = b.getDuckDBInstruction()
 	default:
 		return nil, fmt.Errorf("the driver used %s does not match any known driver by the application", dbType)
 	}
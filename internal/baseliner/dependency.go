@@ -0,0 +1,231 @@
+package baseliner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// dependency tells that an object can only be created after another one
+// foreign keys are deferred when tables refer to each other in a cycle
+type dependency struct {
+	object        string
+	dependsOn     string
+	dependsOnName string
+	foreignKey    bool
+}
+
+// sortByDependencies orders the objects so each comes after the objects it depends on, independent objects keep their order
+func (b *baselilner) sortByDependencies(objects []schemaObject, schemas []string) ([]schemaObject, error) {
+	dependencies, err := b.getDependencies(objects, schemas)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(dependencies) == 0 {
+		return objects, nil
+	}
+
+	positions := make(map[string]int, len(objects))
+	for i, object := range objects {
+		positions[objectKey(object)] = i
+	}
+
+	edges := make(map[string][]dependency)
+	for _, dependency := range dependencies {
+		_, objectFound := positions[dependency.object]
+		_, dependsOnFound := positions[dependency.dependsOn]
+		if !objectFound || !dependsOnFound || dependency.object == dependency.dependsOn {
+			continue
+		}
+
+		edges[dependency.object] = append(edges[dependency.object], dependency)
+	}
+
+	for key := range edges {
+		sort.SliceStable(edges[key], func(i, j int) bool {
+			return positions[edges[key][i].dependsOn] < positions[edges[key][j].dependsOn]
+		})
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	sorted := make([]schemaObject, 0, len(objects))
+	states := make(map[string]int, len(objects))
+	var deferred []dependency
+	var visit func(key string)
+	visit = func(key string) {
+		states[key] = visiting
+		for _, dependency := range edges[key] {
+			switch states[dependency.dependsOn] {
+			case visiting:
+				// A cycle, the dependency cannot be satisfied by ordering
+				deferred = append(deferred, dependency)
+			case visited:
+			default:
+				visit(dependency.dependsOn)
+			}
+		}
+
+		states[key] = visited
+		sorted = append(sorted, objects[positions[key]])
+	}
+
+	for _, object := range objects {
+		if states[objectKey(object)] == 0 {
+			visit(objectKey(object))
+		}
+	}
+
+	return b.deferForeignKeys(sorted, deferred), nil
+}
+
+// deferForeignKeys moves the foreign keys of a cycle out of the table, they are added right after the last table
+func (b *baselilner) deferForeignKeys(objects []schemaObject, deferred []dependency) []schemaObject {
+	deferForeignKey := b.baselineInstruction.deferForeignKey
+	if deferForeignKey == nil {
+		return objects
+	}
+
+	var constraints []schemaObject
+	for _, dependency := range deferred {
+		if !dependency.foreignKey {
+			continue
+		}
+
+		for i, object := range objects {
+			if objectKey(object) != dependency.object {
+				continue
+			}
+
+			var tableConstraints []schemaObject
+			objects[i], tableConstraints = deferForeignKey(object, dependency.dependsOnName)
+			constraints = append(constraints, tableConstraints...)
+		}
+	}
+
+	if len(constraints) == 0 {
+		return objects
+	}
+
+	// Views and routines may come after the tables, the constraints cannot wait behind them
+	afterTables := 0
+	for i, object := range objects {
+		if object.objectType == queryTypeTables {
+			afterTables = i + 1
+		}
+	}
+
+	result := make([]schemaObject, 0, len(objects)+len(constraints))
+	result = append(result, objects[:afterTables]...)
+	result = append(result, constraints...)
+
+	return append(result, objects[afterTables:]...)
+}
+
+func (b *baselilner) getDependencies(objects []schemaObject, schemas []string) ([]dependency, error) {
+	if b.baselineInstruction.parseDependencies {
+		return parsedDependencies(objects), nil
+	}
+
+	if b.baselineInstruction.dependencyQuery == "" {
+		return nil, nil
+	}
+
+	var dependencies []dependency
+	for _, schema := range schemas {
+		schemaDependencies, err := b.queryDependencies(schema)
+		if err != nil {
+			return nil, err
+		}
+
+		dependencies = append(dependencies, schemaDependencies...)
+	}
+
+	return dependencies, nil
+}
+
+// queryDependencies reads the dependencies of the objects of the schema from the catalog
+func (b *baselilner) queryDependencies(schema string) ([]dependency, error) {
+	rows, err := b.db.Query(b.baselineInstruction.dependencyQuery, schema)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get object dependencies of %s, error: %v", schema, err)
+	}
+	defer rows.Close()
+
+	var dependencies []dependency
+	for rows.Next() {
+		var objectType, name, dependsOnSchema, dependsOnType, dependsOnName string
+		var foreignKey bool
+		err := rows.Scan(&objectType, &name, &dependsOnSchema, &dependsOnType, &dependsOnName, &foreignKey)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get object dependency of %s, error: %v", schema, err)
+		}
+
+		// Objects of other schemas are not part of a baseline of the current schema
+		if len(b.schemas) == 0 && dependsOnSchema != schema {
+			continue
+		}
+
+		dependencies = append(dependencies, dependency{
+			object:        objectType + " " + b.objectName(schema, name),
+			dependsOn:     dependsOnType + " " + b.objectName(dependsOnSchema, dependsOnName),
+			dependsOnName: dependsOnName,
+			foreignKey:    foreignKey,
+		})
+	}
+
+	return dependencies, rows.Err()
+}
+
+// parsedDependencies finds the tables and views named in the SQL of the objects, for databases without a dependency catalog
+func parsedDependencies(objects []schemaObject) []dependency {
+	relations := make(map[string]schemaObject)
+	for _, object := range objects {
+		if object.objectType == queryTypeTables || object.objectType == queryTypeViews {
+			relations[strings.ToLower(object.name)] = object
+		}
+	}
+
+	var dependencies []dependency
+	for _, object := range objects {
+		identifiers := sqlIdentifiers(object.sql)
+		found := make(map[string]bool)
+		for i, identifier := range identifiers {
+			if object.objectType == queryTypeTables {
+				// Tables depend on the tables their foreign keys reference
+				if i == 0 || !strings.EqualFold(identifiers[i-1], "references") {
+					continue
+				}
+			} else if object.objectType != queryTypeViews && object.objectType != queryTypeTriggers {
+				continue
+			}
+
+			relation, ok := relations[strings.ToLower(identifier)]
+			if !ok || found[relation.name] {
+				continue
+			}
+
+			found[relation.name] = true
+			dependencies = append(dependencies, dependency{
+				object:        objectKey(object),
+				dependsOn:     objectKey(relation),
+				dependsOnName: relation.name,
+				foreignKey:    object.objectType == queryTypeTables,
+			})
+		}
+	}
+
+	return dependencies
+}
+
+// sqlIdentifiers splits the SQL into words, quoted identifiers lose their quotes
+func sqlIdentifiers(sql string) []string {
+	return strings.FieldsFunc(sql, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '$'
+	})
+}
@@ -0,0 +1,59 @@
+package baseliner
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+// The deferred foreign keys are only built for MySQL, which cannot run in the test module, so they are tested here
+func TestDeferredForeignKeysFollowTables(t *testing.T) {
+	b := &baselilner{baselineInstruction: baselineInstruction{
+		parseDependencies: true,
+		deferForeignKey:   deferMySQLForeignKey,
+	}}
+
+	objects := []schemaObject{
+		{objectType: queryTypeTables, name: "teams", sql: "CREATE TABLE `teams` (\n  `id` int NOT NULL,\n  `captain_id` int DEFAULT NULL,\n  PRIMARY KEY (`id`),\n  CONSTRAINT `fk_teams_players` FOREIGN KEY (`captain_id`) REFERENCES `players` (`id`)\n)"},
+		{objectType: queryTypeTables, name: "players", sql: "CREATE TABLE `players` (\n  `id` int NOT NULL,\n  `team_id` int DEFAULT NULL,\n  PRIMARY KEY (`id`),\n  CONSTRAINT `fk_players_teams` FOREIGN KEY (`team_id`) REFERENCES `teams` (`id`)\n)"},
+		{objectType: queryTypeViews, name: "team_players", sql: "CREATE VIEW `team_players` AS SELECT `p`.`id` FROM `players` `p` JOIN `teams` `t` ON `t`.`id` = `p`.`team_id`"},
+	}
+
+	sorted, err := b.sortByDependencies(objects, nil)
+	if err != nil {
+		t.Fatalf("cannot sort objects, error: %v", err)
+	}
+
+	var baseline strings.Builder
+	for _, object := range sorted {
+		if err := b.writeObject(&baseline, object); err != nil {
+			t.Fatalf("cannot write %s %s, error: %v", object.objectType, object.name, err)
+		}
+	}
+
+	fsys := fstest.MapFS{"baseline.sql": &fstest.MapFile{Data: []byte(baseline.String())}}
+	statements, err := b.readStatements(fsys, "baseline.sql")
+	if err != nil {
+		t.Fatalf("cannot read baseline, error: %v", err)
+	}
+
+	expected := []string{
+		"table players",
+		"table teams",
+		"constraint players.fk_players_teams",
+		"view team_players",
+	}
+	if len(statements) != len(expected) {
+		t.Fatalf("read %d statements, expected %d:\n%s", len(statements), len(expected), baseline.String())
+	}
+
+	for i, statement := range statements {
+		if name := statement.objectType + " " + statement.name; name != expected[i] {
+			t.Errorf("statement %d is %s, expected %s", i, name, expected[i])
+		}
+	}
+
+	if strings.Contains(statements[0].sql, "fk_players_teams") {
+		t.Errorf("the deferred foreign key is still in the table:\n%s", statements[0].sql)
+	}
+}
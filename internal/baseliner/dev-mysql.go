@@ -1,14 +1,16 @@
 package baseliner
 
+import "strings"
+
 func (b *baselilner) getMySQLInstruction() *baselineInstruction {
 	return &baselineInstruction{
 		execute: []string{queryTypeTables, queryTypeViews, queryTypeProcedures, queryTypeFunctions, queryTypeTriggers},
 		listerQueries: map[string]string{
-			queryTypeTables:     "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA = ?",
-			queryTypeViews:      "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.VIEWS WHERE TABLE_SCHEMA = ?",
-			queryTypeProcedures: "SELECT ROUTINE_NAME FROM INFORMATION_SCHEMA.ROUTINES WHERE ROUTINE_TYPE = 'PROCEDURE' AND ROUTINE_SCHEMA = ?",
-			queryTypeFunctions:  "SELECT ROUTINE_NAME FROM INFORMATION_SCHEMA.ROUTINES WHERE ROUTINE_TYPE = 'FUNCTION' AND ROUTINE_SCHEMA = ?",
-			queryTypeTriggers:   "SELECT TRIGGER_NAME FROM INFORMATION_SCHEMA.TRIGGERS WHERE TRIGGER_SCHEMA = ?",
+			queryTypeTables:     "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA = ? ORDER BY TABLE_NAME",
+			queryTypeViews:      "SELECT TABLE_NAME FROM INFORMATION_SCHEMA.VIEWS WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME",
+			queryTypeProcedures: "SELECT ROUTINE_NAME FROM INFORMATION_SCHEMA.ROUTINES WHERE ROUTINE_TYPE = 'PROCEDURE' AND ROUTINE_SCHEMA = ? ORDER BY ROUTINE_NAME",
+			queryTypeFunctions:  "SELECT ROUTINE_NAME FROM INFORMATION_SCHEMA.ROUTINES WHERE ROUTINE_TYPE = 'FUNCTION' AND ROUTINE_SCHEMA = ? ORDER BY ROUTINE_NAME",
+			queryTypeTriggers:   "SELECT TRIGGER_NAME FROM INFORMATION_SCHEMA.TRIGGERS WHERE TRIGGER_SCHEMA = ? ORDER BY EVENT_OBJECT_TABLE, TRIGGER_NAME",
 		},
 		schemaRetrievalQueries: map[string]retrievalInstruction{
 			queryTypeTables: {
@@ -32,6 +34,88 @@ func (b *baselilner) getMySQLInstruction() *baselineInstruction {
 				fieldPosition: 2,
 			},
 		},
+		inspectQueries: inspectQueries{
+			columns: "SELECT TABLE_NAME, COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE = 'NO', COLUMN_DEFAULT, COLUMN_KEY = 'PRI', EXTRA, COLUMN_COMMENT " +
+				"FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? ORDER BY TABLE_NAME, ORDINAL_POSITION",
+			tableComments: "SELECT TABLE_NAME, TABLE_COMMENT FROM INFORMATION_SCHEMA.TABLES WHERE TABLE_SCHEMA = ? AND TABLE_COMMENT <> ''",
+			constraints: "SELECT tc.TABLE_NAME, tc.CONSTRAINT_NAME, tc.CONSTRAINT_NAME, tc.CONSTRAINT_TYPE, k.COLUMN_NAME, " +
+				"k.REFERENCED_TABLE_SCHEMA, k.REFERENCED_TABLE_NAME, k.REFERENCED_COLUMN_NAME, cc.CHECK_CLAUSE " +
+				"FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc " +
+				"LEFT JOIN INFORMATION_SCHEMA.KEY_COLUMN_USAGE k ON k.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND k.TABLE_NAME = tc.TABLE_NAME AND k.CONSTRAINT_NAME = tc.CONSTRAINT_NAME " +
+				"LEFT JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS cc ON cc.CONSTRAINT_SCHEMA = tc.CONSTRAINT_SCHEMA AND cc.CONSTRAINT_NAME = tc.CONSTRAINT_NAME " +
+				"WHERE tc.TABLE_SCHEMA = ? ORDER BY tc.TABLE_NAME, tc.CONSTRAINT_NAME, k.ORDINAL_POSITION",
+			// Primary keys, unique constraints and foreign keys are backed by an index of the same name
+			indexes: "SELECT s.TABLE_NAME, s.INDEX_NAME, s.NON_UNIQUE = 0, COALESCE(s.COLUMN_NAME, s.EXPRESSION) FROM INFORMATION_SCHEMA.STATISTICS s " +
+				"WHERE s.TABLE_SCHEMA = ? AND NOT EXISTS (SELECT 1 FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc " +
+				"WHERE tc.TABLE_SCHEMA = s.TABLE_SCHEMA AND tc.TABLE_NAME = s.TABLE_NAME AND tc.CONSTRAINT_NAME = s.INDEX_NAME) " +
+				"ORDER BY s.TABLE_NAME, s.INDEX_NAME, s.SEQ_IN_INDEX",
+			triggers: "SELECT EVENT_OBJECT_TABLE, TRIGGER_NAME FROM INFORMATION_SCHEMA.TRIGGERS WHERE TRIGGER_SCHEMA = ?",
+		},
 		activeDatabaseSQL: "SELECT DATABASE()",
+		// SHOW CREATE returns one object, the round trips of large schemas overlap instead
+		parallelRetrieval: 8,
+		columnsQuery: "SELECT COLUMN_NAME, COLUMN_TYPE, IS_NULLABLE = 'NO', COLUMN_DEFAULT, COLUMN_KEY = 'PRI', EXTRA " +
+			"FROM INFORMATION_SCHEMA.COLUMNS WHERE TABLE_SCHEMA = ? AND TABLE_NAME = ? ORDER BY ORDINAL_POSITION",
+		dependencyQuery: "SELECT d.object_type, d.object_name, d.dependency_schema, d.dependency_type, d.dependency_name, d.foreign_key FROM (" +
+			"SELECT 'table' AS object_type, CONSTRAINT_SCHEMA AS object_schema, TABLE_NAME AS object_name, UNIQUE_CONSTRAINT_SCHEMA AS dependency_schema, " +
+			"'table' AS dependency_type, REFERENCED_TABLE_NAME AS dependency_name, TRUE AS foreign_key FROM INFORMATION_SCHEMA.REFERENTIAL_CONSTRAINTS " +
+			"UNION ALL " +
+			"SELECT 'view', u.VIEW_SCHEMA, u.VIEW_NAME, u.TABLE_SCHEMA, CASE WHEN t.TABLE_TYPE = 'VIEW' THEN 'view' ELSE 'table' END, u.TABLE_NAME, FALSE " +
+			"FROM INFORMATION_SCHEMA.VIEW_TABLE_USAGE u JOIN INFORMATION_SCHEMA.TABLES t ON t.TABLE_SCHEMA = u.TABLE_SCHEMA AND t.TABLE_NAME = u.TABLE_NAME " +
+			"UNION ALL " +
+			"SELECT 'view', VIEW_SCHEMA, VIEW_NAME, SPECIFIC_SCHEMA, 'function', SPECIFIC_NAME, FALSE FROM INFORMATION_SCHEMA.VIEW_ROUTINE_USAGE" +
+			") d WHERE d.object_schema = ?",
+		deferForeignKey: deferMySQLForeignKey,
+		translation: translationRules{
+			types: map[string]string{
+				typeInteger: "INT", typeBigInt: "BIGINT", typeSmallInt: "SMALLINT", typeDecimal: "DECIMAL", typeReal: "FLOAT", typeDouble: "DOUBLE",
+				typeBoolean: "TINYINT(1)", typeVarchar: "VARCHAR", typeChar: "CHAR", typeText: "TEXT", typeBlob: "LONGBLOB", typeDate: "DATE", typeTime: "TIME",
+				typeTimestamp: "DATETIME", typeTimestampTZ: "DATETIME", typeJSON: "JSON", typeUUID: "CHAR(36)",
+			},
+			autoIncrement:    "AUTO_INCREMENT",
+			varcharLength:    "255",
+			booleans:         [2]string{"0", "1"},
+			currentDate:      "(CURRENT_DATE)",
+			inlineComments:   true,
+			alterForeignKeys: true,
+			indexPrefixTypes: []string{typeText, typeBlob},
+			// MySQL 8.0.13 and later accept expression defaults for these types, literal defaults are refused
+			expressionDefaultTypes: []string{typeText, typeBlob, typeJSON},
+			unquotedDefaults:       true,
+		},
+		identifierQuote:   "`",
+		dropConstraintSQL: "ALTER TABLE %s DROP FOREIGN KEY %s",
+	}
+}
+
+// deferMySQLForeignKey removes the foreign keys referencing the table from SHOW CREATE TABLE and returns them as ALTER TABLE statements
+func deferMySQLForeignKey(table schemaObject, referencedTable string) (schemaObject, []schemaObject) {
+	var lines []string
+	var constraints []schemaObject
+	for _, line := range strings.Split(table.sql, "\n") {
+		definition := strings.TrimSuffix(strings.TrimSpace(line), ",")
+		if !strings.HasPrefix(definition, "CONSTRAINT ") || !strings.Contains(definition, "REFERENCES `"+referencedTable+"`") {
+			lines = append(lines, line)
+			continue
+		}
+
+		constraintName := strings.Trim(strings.Fields(definition)[1], "`")
+		constraints = append(constraints, schemaObject{
+			objectType: queryTypeConstraints,
+			name:       table.name + "." + constraintName,
+			sql:        "ALTER TABLE `" + table.name + "` ADD " + definition,
+		})
+	}
+
+	// The last definition before the closing parenthesis has no trailing comma
+	for i := len(lines) - 1; i > 0; i-- {
+		if strings.HasPrefix(lines[i], ")") {
+			lines[i-1] = strings.TrimSuffix(lines[i-1], ",")
+			break
+		}
 	}
+
+	table.sql = strings.Join(lines, "\n")
+
+	return table, constraints
 }
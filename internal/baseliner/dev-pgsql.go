@@ -1,42 +1,301 @@
 package baseliner
 
 func (b *baselilner) getPostgreSQLInstruction() *baselineInstruction {
+	// When schemas are listed explicitly the generated DDL is schema qualified, otherwise it loads into the current schema
+	tableName, parentName, viewName, matViewName := "quote_ident(c.relname)", "quote_ident(p.relname)", "viewname", "matviewname"
+	typeName, extensionSchema := "quote_ident(t.typname)", "''"
+	sequenceName := "quote_ident(s.sequencename)"
+	if len(b.schemas) > 0 {
+		sequenceName = "quote_ident(s.schemaname) || '.' || quote_ident(s.sequencename)"
+		typeName = "quote_ident(n.nspname) || '.' || quote_ident(t.typname)"
+		extensionSchema = "' WITH SCHEMA ' || quote_ident(n.nspname)"
+		tableName = "quote_ident(n.nspname) || '.' || quote_ident(c.relname)"
+		parentName = "quote_ident(pn.nspname) || '.' || quote_ident(p.relname)"
+		viewName = "quote_ident(schemaname) || '.' || quote_ident(viewname)"
+		matViewName = "quote_ident(schemaname) || '.' || quote_ident(matviewname)"
+	}
+
+	// Serial columns are restored as serial types, as their sequence is owned by the column
+	isSerial := "d.adbin IS NOT NULL AND a.attidentity = '' AND a.attgenerated = '' " +
+		"AND pg_get_expr(d.adbin, d.adrelid) LIKE 'nextval(%%' " +
+		"AND pg_get_serial_sequence(quote_ident(n.nspname) || '.' || quote_ident(c.relname), a.attname) IS NOT NULL"
+
+	columnDefinition := "E'\\t' || quote_ident(a.attname) || ' ' || " +
+		"CASE WHEN " + isSerial + " THEN CASE a.atttypid WHEN 'int2'::regtype THEN 'smallserial' WHEN 'int8'::regtype THEN 'bigserial' ELSE 'serial' END " +
+		"ELSE pg_catalog.format_type(a.atttypid, a.atttypmod) END || " +
+		"CASE WHEN a.attcollation <> 0 AND a.attcollation <> (SELECT typcollation FROM pg_type WHERE oid = a.atttypid) " +
+		"THEN ' COLLATE ' || (SELECT quote_ident(collname) FROM pg_collation WHERE oid = a.attcollation) ELSE '' END || " +
+		"CASE WHEN a.attidentity = 'a' THEN ' GENERATED ALWAYS AS IDENTITY' " +
+		"WHEN a.attidentity = 'd' THEN ' GENERATED BY DEFAULT AS IDENTITY' " +
+		"WHEN a.attgenerated = 's' THEN ' GENERATED ALWAYS AS (' || pg_get_expr(d.adbin, d.adrelid) || ') STORED' " +
+		"WHEN d.adbin IS NOT NULL AND NOT (" + isSerial + ") THEN ' DEFAULT ' || pg_get_expr(d.adbin, d.adrelid) " +
+		"ELSE '' END || " +
+		"CASE WHEN a.attnotnull THEN ' NOT NULL' ELSE '' END"
+
+	// Partitions are created as PARTITION OF their parent, constraints are added after all tables exist
+	createTableSQL := "CASE WHEN c.relispartition THEN " +
+		"'CREATE TABLE ' || " + tableName + " || ' PARTITION OF ' || " +
+		"(SELECT " + parentName + " FROM pg_inherits i JOIN pg_class p ON p.oid = i.inhparent JOIN pg_namespace pn ON pn.oid = p.relnamespace WHERE i.inhrelid = c.oid) || " +
+		"' ' || pg_get_expr(c.relpartbound, c.oid) " +
+		"ELSE 'CREATE ' || CASE c.relpersistence WHEN 'u' THEN 'UNLOGGED ' ELSE '' END || 'TABLE ' || " + tableName + " || E' (\\n' || " +
+		"COALESCE((SELECT string_agg(" + columnDefinition + ", E',\\n' ORDER BY a.attnum) " +
+		"FROM pg_attribute a LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum " +
+		"WHERE a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped), '') || E'\\n)' || " +
+		"CASE WHEN c.relkind = 'p' THEN ' PARTITION BY ' || pg_get_partkeydef(c.oid) ELSE '' END " +
+		"END"
+
+	createConstraintSQL := "'ALTER TABLE ' || " + tableName + " || ' ADD CONSTRAINT ' || quote_ident(con.conname) || ' ' || pg_get_constraintdef(con.oid)"
+
+	commentSQL := "(SELECT string_agg(statement, E';\\n' ORDER BY position) FROM (" +
+		"SELECT 0 AS position, 'COMMENT ON TABLE ' || " + tableName + " || ' IS ' || quote_literal(d.description) AS statement " +
+		"FROM pg_description d WHERE d.classoid = 'pg_class'::regclass AND d.objoid = c.oid AND d.objsubid = 0 " +
+		"UNION ALL " +
+		"SELECT d.objsubid, 'COMMENT ON COLUMN ' || " + tableName + " || '.' || quote_ident(a.attname) || ' IS ' || quote_literal(d.description) " +
+		"FROM pg_description d JOIN pg_attribute a ON a.attrelid = d.objoid AND a.attnum = d.objsubid " +
+		"WHERE d.classoid = 'pg_class'::regclass AND d.objoid = c.oid AND d.objsubid > 0" +
+		") statements)"
+
+	// Objects created by an extension are restored by CREATE EXTENSION
+	notExtensionMember := func(catalog, oid string) string {
+		return "NOT EXISTS (SELECT 1 FROM pg_depend dep WHERE dep.classid = '" + catalog + "'::regclass AND dep.objid = " + oid + " AND dep.deptype = 'e')"
+	}
+
+	createTypeSQL := "'CREATE TYPE ' || " + typeName + " || CASE WHEN t.typtype = 'e' THEN " +
+		"' AS ENUM (' || COALESCE((SELECT string_agg(quote_literal(e.enumlabel), ', ' ORDER BY e.enumsortorder) FROM pg_enum e WHERE e.enumtypid = t.oid), '') || ')' " +
+		"ELSE ' AS (' || COALESCE((SELECT string_agg(quote_ident(a.attname) || ' ' || pg_catalog.format_type(a.atttypid, a.atttypmod), ', ' ORDER BY a.attnum) " +
+		"FROM pg_attribute a WHERE a.attrelid = t.typrelid AND a.attnum > 0 AND NOT a.attisdropped), '') || ')' END"
+
+	createDomainSQL := "'CREATE DOMAIN ' || " + typeName + " || ' AS ' || pg_catalog.format_type(t.typbasetype, t.typtypmod) || " +
+		"CASE WHEN t.typdefault IS NOT NULL THEN ' DEFAULT ' || t.typdefault ELSE '' END || " +
+		"CASE WHEN t.typnotnull THEN ' NOT NULL' ELSE '' END || " +
+		"COALESCE((SELECT string_agg(' CONSTRAINT ' || quote_ident(con.conname) || ' ' || pg_get_constraintdef(con.oid), '' ORDER BY con.conname) " +
+		"FROM pg_constraint con WHERE con.contypid = t.oid AND con.contype = 'c'), '')"
+
+	createSequenceSQL := "'CREATE SEQUENCE ' || " + tableName + " || ' AS ' || pg_catalog.format_type(s.seqtypid, NULL) || " +
+		"' INCREMENT BY ' || s.seqincrement || ' MINVALUE ' || s.seqmin || ' MAXVALUE ' || s.seqmax || " +
+		"' START WITH ' || s.seqstart || ' CACHE ' || s.seqcache || CASE WHEN s.seqcycle THEN ' CYCLE' ELSE ' NO CYCLE' END"
+
+	// Row level security is enabled on the table before its policies are created
+	policySQL := "array_to_string(ARRAY[" +
+		"CASE WHEN c.relrowsecurity THEN 'ALTER TABLE ' || " + tableName + " || ' ENABLE ROW LEVEL SECURITY' END, " +
+		"CASE WHEN c.relforcerowsecurity THEN 'ALTER TABLE ' || " + tableName + " || ' FORCE ROW LEVEL SECURITY' END, " +
+		"(SELECT string_agg('CREATE POLICY ' || quote_ident(p.polname) || ' ON ' || " + tableName + " || " +
+		"CASE WHEN p.polpermissive THEN '' ELSE ' AS RESTRICTIVE' END || " +
+		"' FOR ' || CASE p.polcmd WHEN 'r' THEN 'SELECT' WHEN 'a' THEN 'INSERT' WHEN 'w' THEN 'UPDATE' WHEN 'd' THEN 'DELETE' ELSE 'ALL' END || " +
+		"CASE WHEN p.polroles = '{0}' THEN '' ELSE ' TO ' || (SELECT string_agg(quote_ident(r.rolname), ', ' ORDER BY r.rolname) FROM pg_roles r WHERE r.oid = ANY(p.polroles)) END || " +
+		"COALESCE(' USING (' || pg_get_expr(p.polqual, p.polrelid) || ')', '') || " +
+		"COALESCE(' WITH CHECK (' || pg_get_expr(p.polwithcheck, p.polrelid) || ')', ''), E';\\n' ORDER BY p.polname) " +
+		"FROM pg_policy p WHERE p.polrelid = c.oid)" +
+		"], E';\\n')"
+
+	// The owner holds every privilege implicitly, only privileges granted to other roles are restored
+	grantSQL := "string_agg('GRANT ' || a.privilege_type || ' ON ' || CASE c.relkind WHEN 'S' THEN 'SEQUENCE ' ELSE 'TABLE ' END || " + tableName + " || " +
+		"' TO ' || CASE WHEN a.grantee = 0 THEN 'PUBLIC' ELSE quote_ident(pg_get_userbyid(a.grantee)) END || " +
+		"CASE WHEN a.is_grantable THEN ' WITH GRANT OPTION' ELSE '' END, E';\\n' ORDER BY pg_get_userbyid(a.grantee), a.privilege_type)"
+	grantFrom := "FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace CROSS JOIN LATERAL aclexplode(c.relacl) a " +
+		"WHERE n.nspname = '%s' AND a.grantee <> c.relowner"
+
+	// retrieval queries the SQL of one object by name, and in bulk the SQL of every object of the schema, from filters by the schema
+	retrieval := func(sqlExpr, from, nameExpr string) retrievalInstruction {
+		return retrievalInstruction{
+			query:                "SELECT " + sqlExpr + " " + from + " AND " + nameExpr + " = '%s'",
+			bulkQuery:            "SELECT " + nameExpr + ", " + sqlExpr + " " + from,
+			dbNameShouldBePassed: true,
+		}
+	}
+	relations := "FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = '%s'"
+
+	// Catalog objects are mapped to the baseline object they are created by, view dependencies are recorded on their rewrite rule
+	dependencySQL := "WITH objects(classid, objid, object_type, schema_name, object_name) AS (" +
+		"SELECT 'pg_class'::regclass::oid, c.oid, CASE c.relkind WHEN 'v' THEN 'view' WHEN 'm' THEN 'materialView' WHEN 'S' THEN 'sequence' ELSE 'table' END, n.nspname, c.relname " +
+		"FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace WHERE c.relkind IN ('r', 'p', 'v', 'm', 'S') " +
+		"UNION ALL " +
+		"SELECT 'pg_class'::regclass::oid, i.oid, 'index', n.nspname, i.relname FROM pg_index x JOIN pg_class i ON i.oid = x.indexrelid JOIN pg_namespace n ON n.oid = i.relnamespace " +
+		"UNION ALL " +
+		"SELECT 'pg_rewrite'::regclass::oid, r.oid, CASE c.relkind WHEN 'm' THEN 'materialView' ELSE 'view' END, n.nspname, c.relname " +
+		"FROM pg_rewrite r JOIN pg_class c ON c.oid = r.ev_class JOIN pg_namespace n ON n.oid = c.relnamespace WHERE c.relkind IN ('v', 'm') " +
+		"UNION ALL " +
+		"SELECT 'pg_attrdef'::regclass::oid, ad.oid, 'table', n.nspname, c.relname FROM pg_attrdef ad JOIN pg_class c ON c.oid = ad.adrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+		"UNION ALL " +
+		"SELECT 'pg_type'::regclass::oid, a.oid, CASE t.typtype WHEN 'd' THEN 'domain' ELSE 'type' END, n.nspname, t.typname FROM pg_type a " +
+		"JOIN pg_type t ON t.oid = CASE WHEN a.typelem <> 0 AND (SELECT e.typarray FROM pg_type e WHERE e.oid = a.typelem) = a.oid THEN a.typelem ELSE a.oid END " +
+		"JOIN pg_namespace n ON n.oid = t.typnamespace " +
+		"WHERE t.typtype IN ('e', 'd') OR (t.typtype = 'c' AND (SELECT c.relkind FROM pg_class c WHERE c.oid = t.typrelid) = 'c') " +
+		"UNION ALL " +
+		"SELECT 'pg_proc'::regclass::oid, p.oid, CASE p.prokind WHEN 'p' THEN 'procedure' ELSE 'function' END, n.nspname, p.proname " +
+		"FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace WHERE p.prokind IN ('f', 'p') " +
+		"UNION ALL " +
+		"SELECT 'pg_constraint'::regclass::oid, con.oid, 'constraint', n.nspname, c.relname || '.' || con.conname " +
+		"FROM pg_constraint con JOIN pg_class c ON c.oid = con.conrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+		"UNION ALL " +
+		"SELECT 'pg_trigger'::regclass::oid, t.oid, 'trigger', n.nspname, c.relname || '.' || t.tgname " +
+		"FROM pg_trigger t JOIN pg_class c ON c.oid = t.tgrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+		"UNION ALL " +
+		"SELECT 'pg_extension'::regclass::oid, e.oid, 'extension', n.nspname, e.extname FROM pg_extension e JOIN pg_namespace n ON n.oid = e.extnamespace" +
+		") " +
+		"SELECT DISTINCT o.object_type, o.object_name, r.schema_name, r.object_type, r.object_name, false " +
+		"FROM pg_depend d JOIN objects o ON o.classid = d.classid AND o.objid = d.objid " +
+		"JOIN objects r ON r.classid = d.refclassid AND r.objid = d.refobjid " +
+		"WHERE d.deptype = 'n' AND o.schema_name = $1 " +
+		"UNION " +
+		"SELECT 'table', c.relname, rn.nspname, 'table', rc.relname, true FROM pg_constraint con " +
+		"JOIN pg_class c ON c.oid = con.conrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+		"JOIN pg_class rc ON rc.oid = con.confrelid JOIN pg_namespace rn ON rn.oid = rc.relnamespace " +
+		"WHERE con.contype = 'f' AND n.nspname = $1"
+
+	// Sequences of serial and identity columns continue after the saved rows
+	sequenceValuesSQL := "SELECT 'SELECT setval(' || quote_literal(" + sequenceName + ") || ', ' || s.last_value || ')' " +
+		"FROM pg_attribute a CROSS JOIN LATERAL pg_get_serial_sequence($1, a.attname) AS serial(name) " +
+		"JOIN pg_sequences s ON quote_ident(s.schemaname) || '.' || quote_ident(s.sequencename) = serial.name " +
+		"WHERE a.attrelid = $1::regclass AND a.attnum > 0 AND NOT a.attisdropped AND s.last_value IS NOT NULL ORDER BY a.attnum"
+
 	return &baselineInstruction{
-		execute: []string{queryTypeTables, queryTypeIndex, queryTypeViews, queryTypeMaterialViews, queryTypeFunctions, queryTypeProcedures},
+		execute: []string{
+			queryTypeExtensions,
+			queryTypeTypes,
+			queryTypeDomains,
+			queryTypeSequences,
+			queryTypeTables,
+			queryTypeConstraints,
+			queryTypeIndex,
+			queryTypeComments,
+			queryTypeViews,
+			queryTypeMaterialViews,
+			queryTypeFunctions,
+			queryTypeProcedures,
+			queryTypeTriggers,
+			queryTypePolicies,
+			queryTypeGrants,
+		},
+		createSchemaSQL: "SELECT 'CREATE SCHEMA IF NOT EXISTS ' || quote_ident($1)",
 		listerQueries: map[string]string{
-			queryTypeTables:        "SELECT tablename FROM pg_catalog.pg_tables WHERE schemaname = $1",
-			queryTypeIndex:         "SELECT x.indexrelid FROM pg_index x JOIN pg_class c ON c.oid = x.indrelid JOIN pg_class i ON i.oid = x.indexrelid JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 ORDER BY c.relname, i.relname",
-			queryTypeViews:         "SELECT viewname FROM pg_catalog.pg_views WHERE schemaname = $1",
-			queryTypeMaterialViews: "SELECT matviewname FROM pg_catalog.pg_matviews WHERE schemaname = $1",
-			queryTypeFunctions:     "SELECT routine_name FROM information_schema.routines WHERE routine_type = 'FUNCTION' AND specific_schema = $1",
-			queryTypeProcedures:    "SELECT routine_name FROM information_schema.routines WHERE routine_type = 'PROCEDURE' AND specific_schema = $1",
+			queryTypeExtensions: "SELECT e.extname FROM pg_extension e JOIN pg_namespace n ON n.oid = e.extnamespace " +
+				"WHERE n.nspname = $1 AND e.extname <> 'plpgsql' ORDER BY e.extname",
+			// Enums come before composite types, as composite types may have enum fields
+			queryTypeTypes: "SELECT t.typname FROM pg_type t JOIN pg_namespace n ON n.oid = t.typnamespace " +
+				"WHERE n.nspname = $1 AND (t.typtype = 'e' OR (t.typtype = 'c' AND (SELECT c.relkind FROM pg_class c WHERE c.oid = t.typrelid) = 'c')) " +
+				"AND " + notExtensionMember("pg_type", "t.oid") + " ORDER BY t.typtype DESC, t.typname",
+			queryTypeDomains: "SELECT t.typname FROM pg_type t JOIN pg_namespace n ON n.oid = t.typnamespace " +
+				"WHERE n.nspname = $1 AND t.typtype = 'd' AND " + notExtensionMember("pg_type", "t.oid") + " ORDER BY t.typname",
+			// Sequences owned by serial and identity columns are created with their column
+			queryTypeSequences: "SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"WHERE c.relkind = 'S' AND n.nspname = $1 " +
+				"AND NOT EXISTS (SELECT 1 FROM pg_depend dep WHERE dep.classid = 'pg_class'::regclass AND dep.objid = c.oid AND dep.deptype IN ('a', 'i', 'e')) " +
+				"ORDER BY c.relname",
+			queryTypeTables: "SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"WHERE c.relkind IN ('r', 'p') AND n.nspname = $1 AND " + notExtensionMember("pg_class", "c.oid") + " ORDER BY c.relispartition, c.relname",
+			// Primary key, unique and check constraints come first, as foreign keys refer to primary keys and unique constraints
+			queryTypeConstraints: "SELECT c.relname || '.' || con.conname FROM pg_constraint con " +
+				"JOIN pg_class c ON c.oid = con.conrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"WHERE n.nspname = $1 AND con.contype IN ('p', 'u', 'c', 'x', 'f') AND con.conislocal " +
+				"ORDER BY CASE con.contype WHEN 'f' THEN 1 ELSE 0 END, c.relname, con.conname",
+			// Indexes of constraints are created by the constraint, partition indexes by the index of the parent
+			queryTypeIndex: "SELECT i.relname FROM pg_index x JOIN pg_class c ON c.oid = x.indrelid JOIN pg_class i ON i.oid = x.indexrelid " +
+				"JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND NOT i.relispartition " +
+				"AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = x.indexrelid AND con.contype IN ('p', 'u', 'x')) " +
+				"ORDER BY c.relname, i.relname",
+			queryTypeComments: "SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"WHERE c.relkind IN ('r', 'p') AND n.nspname = $1 " +
+				"AND EXISTS (SELECT 1 FROM pg_description d WHERE d.classoid = 'pg_class'::regclass AND d.objoid = c.oid) ORDER BY c.relname",
+			queryTypeViews: "SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"WHERE c.relkind = 'v' AND n.nspname = $1 AND " + notExtensionMember("pg_class", "c.oid") + " ORDER BY c.relname",
+			queryTypeMaterialViews: "SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"WHERE c.relkind = 'm' AND n.nspname = $1 AND " + notExtensionMember("pg_class", "c.oid") + " ORDER BY c.relname",
+			queryTypeFunctions: "SELECT p.proname FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace " +
+				"WHERE n.nspname = $1 AND p.prokind = 'f' AND " + notExtensionMember("pg_proc", "p.oid") + " ORDER BY p.proname",
+			queryTypeProcedures: "SELECT p.proname FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace " +
+				"WHERE n.nspname = $1 AND p.prokind = 'p' AND " + notExtensionMember("pg_proc", "p.oid") + " ORDER BY p.proname",
+			// Internal triggers implement constraints, triggers with a parent are cloned to partitions by the parent trigger
+			queryTypeTriggers: "SELECT c.relname || '.' || t.tgname FROM pg_trigger t JOIN pg_class c ON c.oid = t.tgrelid " +
+				"JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND NOT t.tgisinternal AND t.tgparentid = 0 " +
+				"ORDER BY c.relname, t.tgname",
+			queryTypePolicies: "SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND (c.relrowsecurity OR EXISTS (SELECT 1 FROM pg_policy p WHERE p.polrelid = c.oid)) " +
+				"ORDER BY c.relname",
+			queryTypeGrants: "SELECT c.relname FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"WHERE n.nspname = $1 AND c.relkind IN ('r', 'p', 'v', 'm', 'S') " +
+				"AND EXISTS (SELECT 1 FROM aclexplode(c.relacl) a WHERE a.grantee <> c.relowner) ORDER BY c.relname",
 		},
 		schemaRetrievalQueries: map[string]retrievalInstruction{
-			queryTypeTables: {
-				query:                "SELECT 'CREATE TABLE ' || c.relname || E'(\\n' || array_to_string(array_agg(E'\\t' || a.attname || ' ' || pg_catalog.format_type(a.atttypid, a.atttypmod)), E',\\n') || E'\\n)' AS create_table_sql FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace JOIN pg_attribute a ON a.attrelid = c.oid WHERE c.relkind = 'r' AND n.nspname = '%s' AND a.attnum > 0 and c.relname = '%s' GROUP BY c.relname",
-				dbNameShouldBePassed: true,
-			},
-			queryTypeIndex: {
-				query:                "SELECT pg_catalog.pg_get_indexdef(i.oid) AS create_index_sql FROM pg_index x JOIN pg_class i ON i.oid = x.indexrelid JOIN pg_namespace n ON n.oid = i.relnamespace WHERE n.nspname = '%s' and x.indexrelid = '%s'",
-				dbNameShouldBePassed: true,
-			},
-			queryTypeViews: {
-				query:                "SELECT ('CREATE VIEW ' || viewname || ' AS ' || definition) as sql FROM pg_catalog.pg_views WHERE schemaname = '%s' and viewname = '%s';",
+			queryTypeExtensions: retrieval(
+				"'CREATE EXTENSION IF NOT EXISTS ' || quote_ident(e.extname) || "+extensionSchema,
+				"FROM pg_extension e JOIN pg_namespace n ON n.oid = e.extnamespace WHERE n.nspname = '%s'",
+				"e.extname",
+			),
+			queryTypeTypes:       retrieval(createTypeSQL, "FROM pg_type t JOIN pg_namespace n ON n.oid = t.typnamespace WHERE n.nspname = '%s'", "t.typname"),
+			queryTypeDomains:     retrieval(createDomainSQL, "FROM pg_type t JOIN pg_namespace n ON n.oid = t.typnamespace WHERE n.nspname = '%s'", "t.typname"),
+			queryTypeSequences:   retrieval(createSequenceSQL, "FROM pg_sequence s JOIN pg_class c ON c.oid = s.seqrelid JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = '%s'", "c.relname"),
+			queryTypeTables:      retrieval(createTableSQL, relations+" AND c.relkind IN ('r', 'p')", "c.relname"),
+			queryTypeConstraints: retrieval(createConstraintSQL, "FROM pg_constraint con JOIN pg_class c ON c.oid = con.conrelid JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = '%s'", "c.relname || '.' || con.conname"),
+			queryTypeIndex: retrieval(
+				"pg_catalog.pg_get_indexdef(i.oid)",
+				"FROM pg_index x JOIN pg_class i ON i.oid = x.indexrelid JOIN pg_namespace n ON n.oid = i.relnamespace WHERE n.nspname = '%s'",
+				"i.relname",
+			),
+			queryTypeComments:      retrieval(commentSQL, relations+" AND c.relkind IN ('r', 'p')", "c.relname"),
+			queryTypeViews:         retrieval("'CREATE VIEW ' || "+viewName+" || ' AS ' || definition", "FROM pg_catalog.pg_views WHERE schemaname = '%s'", "viewname"),
+			queryTypeMaterialViews: retrieval("'CREATE MATERIALIZED VIEW ' || "+matViewName+" || ' AS ' || definition", "FROM pg_catalog.pg_matviews WHERE schemaname = '%s'", "matviewname"),
+			// Aggregates have no function definition
+			queryTypeFunctions:  retrieval("pg_get_functiondef(p.oid)", "FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace WHERE n.nspname = '%s' AND p.prokind = 'f'", "p.proname"),
+			queryTypeProcedures: retrieval("pg_get_functiondef(p.oid)", "FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace WHERE n.nspname = '%s' AND p.prokind = 'p'", "p.proname"),
+			queryTypeTriggers: retrieval(
+				"pg_get_triggerdef(t.oid)",
+				"FROM pg_trigger t JOIN pg_class c ON c.oid = t.tgrelid JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = '%s' AND NOT t.tgisinternal",
+				"c.relname || '.' || t.tgname",
+			),
+			queryTypePolicies: retrieval(policySQL, relations+" AND c.relkind IN ('r', 'p')", "c.relname"),
+			queryTypeGrants: {
+				query:                "SELECT " + grantSQL + " " + grantFrom + " AND c.relname = '%s'",
+				bulkQuery:            "SELECT c.relname, " + grantSQL + " " + grantFrom + " GROUP BY n.nspname, c.relname",
 				dbNameShouldBePassed: true,
 			},
-			queryTypeMaterialViews: {
-				query:                "SELECT ('CREATE MATERIALIZED VIEW ' || matviewname || ' AS ' || definition) as sql FROM pg_catalog.pg_matviews WHERE schemaname = '%s' AND matviewname = '%s'",
-				dbNameShouldBePassed: true,
-			},
-			queryTypeFunctions: {
-				query:                "SELECT pg_get_functiondef(p.oid) as sql FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace WHERE n.nspname = '%s' and p.proname = '%s'",
-				dbNameShouldBePassed: true,
-			},
-			queryTypeProcedures: {
-				query:                "SELECT pg_get_functiondef(p.oid) as sql FROM pg_proc p JOIN pg_namespace n ON n.oid = p.pronamespace WHERE n.nspname = '%s' AND p.prokind = 'p' and p.proname = '%s'",
-				dbNameShouldBePassed: true,
+		},
+		inspectQueries: inspectQueries{
+			columns: "SELECT c.relname, a.attname, pg_catalog.format_type(a.atttypid, a.atttypmod), a.attnotnull, pg_get_expr(d.adbin, d.adrelid), " +
+				"EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conrelid = c.oid AND con.contype = 'p' AND a.attnum = ANY(con.conkey)), " +
+				"CASE WHEN a.attidentity <> '' THEN 'identity' WHEN a.attgenerated <> '' THEN 'generated' ELSE '' END, col_description(c.oid, a.attnum) " +
+				"FROM pg_attribute a JOIN pg_class c ON c.oid = a.attrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum " +
+				"WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped ORDER BY c.relname, a.attnum",
+			tableComments: "SELECT c.relname, obj_description(c.oid, 'pg_class') FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND obj_description(c.oid, 'pg_class') IS NOT NULL",
+			constraints: "SELECT c.relname, con.conname, con.conname, " +
+				"CASE con.contype WHEN 'p' THEN 'PRIMARY KEY' WHEN 'u' THEN 'UNIQUE' WHEN 'f' THEN 'FOREIGN KEY' WHEN 'c' THEN 'CHECK' ELSE 'EXCLUDE' END, " +
+				"a.attname, rn.nspname, rc.relname, ra.attname, CASE WHEN con.contype = 'c' THEN pg_get_constraintdef(con.oid) END " +
+				"FROM pg_constraint con JOIN pg_class c ON c.oid = con.conrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"LEFT JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, position) ON true " +
+				"LEFT JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum " +
+				"LEFT JOIN pg_class rc ON rc.oid = con.confrelid LEFT JOIN pg_namespace rn ON rn.oid = rc.relnamespace " +
+				"LEFT JOIN pg_attribute ra ON ra.attrelid = con.confrelid AND ra.attnum = k.refattnum " +
+				"WHERE n.nspname = $1 AND con.contype IN ('p', 'u', 'f', 'c', 'x') ORDER BY c.relname, con.conname, k.position",
+			indexes: "SELECT c.relname, i.relname, x.indisunique, pg_get_indexdef(x.indexrelid, k.position, true) " +
+				"FROM pg_index x JOIN pg_class c ON c.oid = x.indrelid JOIN pg_class i ON i.oid = x.indexrelid JOIN pg_namespace n ON n.oid = c.relnamespace " +
+				"CROSS JOIN LATERAL generate_series(1, x.indnkeyatts) AS k(position) " +
+				"WHERE n.nspname = $1 AND NOT i.relispartition " +
+				"AND NOT EXISTS (SELECT 1 FROM pg_constraint con WHERE con.conindid = x.indexrelid AND con.contype IN ('p', 'u', 'x')) " +
+				"ORDER BY c.relname, i.relname, k.position",
+			triggers: "SELECT c.relname, c.relname || '.' || t.tgname FROM pg_trigger t JOIN pg_class c ON c.oid = t.tgrelid " +
+				"JOIN pg_namespace n ON n.oid = c.relnamespace WHERE n.nspname = $1 AND NOT t.tgisinternal",
+		},
+		activeDatabaseSQL:  "SELECT current_schema()",
+		identifierQuote:    "\"",
+		dropConstraintSQL:  "ALTER TABLE %s DROP CONSTRAINT IF EXISTS %s",
+		dropTriggerOnTable: true,
+		translation: translationRules{
+			types: map[string]string{
+				typeInteger: "INTEGER", typeBigInt: "BIGINT", typeSmallInt: "SMALLINT", typeDecimal: "NUMERIC", typeReal: "REAL", typeDouble: "DOUBLE PRECISION",
+				typeBoolean: "BOOLEAN", typeVarchar: "VARCHAR", typeChar: "CHAR", typeText: "TEXT", typeBlob: "BYTEA", typeDate: "DATE", typeTime: "TIME",
+				typeTimestamp: "TIMESTAMP", typeTimestampTZ: "TIMESTAMPTZ", typeJSON: "JSONB", typeUUID: "UUID",
 			},
+			autoIncrement:     "GENERATED BY DEFAULT AS IDENTITY",
+			materializedViews: true,
+			booleans:          [2]string{"FALSE", "TRUE"},
+			currentDate:       "CURRENT_DATE",
+			commentOn:         true,
+			alterForeignKeys:  true,
 		},
-		activeDatabaseSQL: "SELECT current_schema()",
+		transactionalDDL:    true,
+		dependencyQuery:     dependencySQL,
+		sequenceValuesQuery: sequenceValuesSQL,
 	}
 }
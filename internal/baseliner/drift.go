@@ -0,0 +1,129 @@
+package baseliner
+
+import (
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+// Drift kinds, compared to the snapshot
+const (
+	DriftAdded   = "added"
+	DriftRemoved = "removed"
+	DriftChanged = "changed"
+)
+
+// Drift is an object of the live database which differs from the snapshot
+type Drift struct {
+	Kind       string
+	ObjectType string
+	Name       string
+	Expected   string
+	Actual     string
+}
+
+// Drift compares the live database with a snapshot saved by SaveSnapshot, or with the baseline if fileName is empty
+func (b *baselilner) Drift(fsys fs.FS, fileName string) ([]Drift, error) {
+	drifts, err := b.drift(fsys, fileName)
+	return drifts, objectError("", "", err)
+}
+
+func (b *baselilner) drift(fsys fs.FS, fileName string) ([]Drift, error) {
+	err := b.loadInstructions()
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []schemaObject
+	if fileName == "" {
+		fileName = baselineFileName
+		statements, err = b.readBaseline(fsys)
+	} else {
+		statements, err = b.readStatements(fsys, fileName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	expected := make(map[string]schemaObject, len(statements))
+	for _, statement := range statements {
+		if statement.objectType == queryTypeData || !b.isIncluded(statement.objectType, statement.name) {
+			continue
+		}
+
+		if statement.objectType == "" {
+			return nil, fmt.Errorf("snapshot %s has no object annotations, save it again", fileName)
+		}
+
+		key := objectKey(statement)
+		if object, ok := expected[key]; ok {
+			statement.sql = object.sql + statement.sql
+		}
+
+		expected[key] = statement
+	}
+
+	var drifts []Drift
+	err = b.walkSchemaObjects(func(object schemaObject) error {
+		key := objectKey(object)
+		snapshot, ok := expected[key]
+		if !ok {
+			drifts = append(drifts, newDrift(DriftAdded, object, "", object.sql))
+			return nil
+		}
+
+		delete(expected, key)
+		if normalizeSQL(snapshot.sql) != normalizeSQL(object.sql) {
+			drifts = append(drifts, newDrift(DriftChanged, object, snapshot.sql, object.sql))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range expected {
+		drifts = append(drifts, newDrift(DriftRemoved, snapshot, snapshot.sql, ""))
+	}
+
+	sort.Slice(drifts, func(i, j int) bool {
+		if drifts[i].ObjectType != drifts[j].ObjectType {
+			return drifts[i].ObjectType < drifts[j].ObjectType
+		}
+
+		return drifts[i].Name < drifts[j].Name
+	})
+
+	return drifts, nil
+}
+
+func newDrift(kind string, object schemaObject, expected, actual string) Drift {
+	return Drift{
+		Kind:       kind,
+		ObjectType: object.objectType,
+		Name:       object.name,
+		Expected:   normalizeSQL(expected),
+		Actual:     normalizeSQL(actual),
+	}
+}
+
+func objectKey(object schemaObject) string {
+	return object.objectType + " " + object.name
+}
+
+// normalizeSQL drops the blank and comment lines and the closing semicolon, as they are dropped when a baseline is read
+func normalizeSQL(sql string) string {
+	var lines []string
+	for _, line := range strings.Split(sql, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		lines = append(lines, strings.TrimRight(line, " \t\r"))
+	}
+
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(strings.Join(lines, "\n")), ";"))
+}
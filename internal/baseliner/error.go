@@ -0,0 +1,45 @@
+package baseliner
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ObjectError is returned by every failing baseliner operation
+// ObjectType and Name are the object it failed on, they are empty when the failure is not bound to one object
+type ObjectError struct {
+	ObjectType string
+	Name       string
+	Err        error
+}
+
+// Error implements error
+func (e *ObjectError) Error() string {
+	switch {
+	case e.ObjectType == "":
+		return e.Err.Error()
+	case e.Name == "":
+		return fmt.Sprintf("%s: %v", e.ObjectType, e.Err)
+	default:
+		return fmt.Sprintf("%s %s: %v", e.ObjectType, e.Name, e.Err)
+	}
+}
+
+// Unwrap returns the underlying error
+func (e *ObjectError) Unwrap() error {
+	return e.Err
+}
+
+// objectError wraps err, unless it already tells the object it failed on
+func objectError(objectType, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var objectErr *ObjectError
+	if errors.As(err, &objectErr) {
+		return err
+	}
+
+	return &ObjectError{ObjectType: objectType, Name: name, Err: err}
+}
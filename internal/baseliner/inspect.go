@@ -0,0 +1,116 @@
+package baseliner
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// Object types returned by Objects
+const (
+	ObjectTypeTable        = queryTypeTables
+	ObjectTypeIndex        = queryTypeIndex
+	ObjectTypeView         = queryTypeViews
+	ObjectTypeMaterialView = queryTypeMaterialViews
+	ObjectTypeProcedure    = queryTypeProcedures
+	ObjectTypeFunction     = queryTypeFunctions
+	ObjectTypeTrigger      = queryTypeTriggers
+	ObjectTypeSequence     = queryTypeSequences
+	ObjectTypeSchema       = queryTypeSchema
+	ObjectTypeConstraint   = queryTypeConstraints
+	ObjectTypeComment      = queryTypeComments
+	ObjectTypeExtension    = queryTypeExtensions
+	ObjectTypeType         = queryTypeTypes
+	ObjectTypeDomain       = queryTypeDomains
+	ObjectTypePolicy       = queryTypePolicies
+	ObjectTypeGrant        = queryTypeGrants
+)
+
+// Object is a database object and the SQL creating it
+type Object struct {
+	Type string
+	Name string
+	SQL  string
+}
+
+// Column is a table column, Default is the raw default expression of the catalog
+// Comment is only read by Inspect
+type Column struct {
+	Name       string
+	Type       string
+	NotNull    bool
+	Default    sql.NullString
+	PrimaryKey bool
+	Extra      string
+	Comment    string
+}
+
+// Objects returns every object of the schema, in the order they are saved into the baseline
+func (b *baselilner) Objects() ([]Object, error) {
+	objects, err := b.objects()
+	return objects, objectError("", "", err)
+}
+
+func (b *baselilner) objects() ([]Object, error) {
+	err := b.loadInstructions()
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []Object
+	err = b.walkSchemaObjects(func(object schemaObject) error {
+		objects = append(objects, Object{Type: object.objectType, Name: object.name, SQL: object.sql})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+// Columns returns the columns of a table in their ordinal position
+func (b *baselilner) Columns(tableName string) ([]Column, error) {
+	columns, err := b.columns(tableName)
+	return columns, objectError(queryTypeTables, tableName, err)
+}
+
+func (b *baselilner) columns(tableName string) ([]Column, error) {
+	err := b.loadInstructions()
+	if err != nil {
+		return nil, err
+	}
+
+	if b.baselineInstruction.columnsQuery == "" {
+		return nil, fmt.Errorf("column introspection is not supported for %s database type", b.dialect)
+	}
+
+	databaseName, err := b.getActiveDatabaseName()
+	if err != nil {
+		return nil, err
+	}
+
+	sqlParams := make([]any, 0)
+	if databaseName != "" {
+		sqlParams = append(sqlParams, databaseName)
+	}
+	sqlParams = append(sqlParams, tableName)
+
+	rows, err := b.db.Query(b.baselineInstruction.columnsQuery, sqlParams...)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get columns of table %s, error: %v", tableName, err)
+	}
+	defer rows.Close()
+
+	var columns []Column
+	for rows.Next() {
+		var column Column
+		err := rows.Scan(&column.Name, &column.Type, &column.NotNull, &column.Default, &column.PrimaryKey, &column.Extra)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get column of table %s, error: %v", tableName, err)
+		}
+
+		columns = append(columns, column)
+	}
+
+	return columns, rows.Err()
+}
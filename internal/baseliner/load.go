@@ -2,26 +2,188 @@ package baseliner
 
 import (
 	"bufio"
+	"database/sql"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
+	"path"
 	"strings"
+
+	"github.com/olbrichattila/godbmigrator/config"
 )
 
-func (b *baselilner) Load(migrationFilePath string) error {
-	filename := migrationFilePath + "/baseline.sql"
+// ErrDatabaseNotEmpty is returned by Load when the database already has objects and the load is not forced
+var ErrDatabaseNotEmpty = errors.New("database is not empty")
+
+// LoadOptions of Load, Force loads the baseline into a database which already has objects
+// Record is called after the statements, in the same transaction when the dialect has transactional DDL
+type LoadOptions struct {
+	Force  bool
+	Record func(db Execer) error
+}
+
+// Execer executes a statement, on the database or on the transaction of the load
+type Execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// Load executes the baseline, in one transaction when the dialect has transactional DDL
+func (b *baselilner) Load(fsys fs.FS, options LoadOptions) error {
+	return objectError("", "", b.load(fsys, options))
+}
+
+func (b *baselilner) load(fsys fs.FS, options LoadOptions) error {
+	err := b.loadInstructions()
+	if err != nil {
+		return err
+	}
+
+	statements, err := b.statements(fsys)
+	if err != nil {
+		return err
+	}
+
+	if !options.Force {
+		err = b.checkEmpty()
+		if err != nil {
+			return err
+		}
+	}
+
+	return b.execInTransaction(config.BaselineLoadingObject, statements, options.Record)
+}
+
+// execInTransaction executes the statements and then record, in one transaction when the dialect has transactional DDL
+func (b *baselilner) execInTransaction(eventType int, statements []Object, record func(db Execer) error) error {
+	if !b.baselineInstruction.transactionalDDL {
+		return b.execAndRecord(b.db, eventType, statements, record)
+	}
+
+	tx, err := b.db.Begin()
+	if err != nil {
+		return fmt.Errorf("cannot start baseline transaction, error: %v", err)
+	}
+
+	err = b.execAndRecord(tx, eventType, statements, record)
+	if err != nil {
+		rollbackErr := tx.Rollback()
+		if rollbackErr != nil {
+			return fmt.Errorf("%v, rollback error: %v", err, rollbackErr)
+		}
+
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("cannot commit baseline transaction, error: %v", err)
+	}
+
+	return nil
+}
 
-	file, err := os.Open(filename)
+// Statements returns the statements Load would execute, in order, without executing them
+func (b *baselilner) Statements(fsys fs.FS) ([]Object, error) {
+	objects, err := b.statements(fsys)
+	return objects, objectError("", "", err)
+}
+
+func (b *baselilner) statements(fsys fs.FS) ([]Object, error) {
+	statements, err := b.readBaseline(fsys)
 	if err != nil {
-		return fmt.Errorf("file opening error %s Error:%v", filename, err)
+		return nil, err
+	}
+
+	var objects []Object
+	for _, statement := range statements {
+		if !b.isIncluded(statement.objectType, statement.name) {
+			continue
+		}
+
+		objects = append(objects, Object{Type: statement.objectType, Name: statement.name, SQL: statement.sql})
+	}
+
+	return objects, nil
+}
+
+// checkEmpty refuses the load if the introspected database has an object which passes the filter
+func (b *baselilner) checkEmpty() error {
+	schemas, err := b.getSchemaNames()
+	if err != nil {
+		return err
+	}
+
+	var existing []string
+	err = b.walkObjectNames(schemas, func(objectType string, _, names []string) error {
+		for _, name := range names {
+			existing = append(existing, objectType+" "+name)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(existing) > 0 {
+		return fmt.Errorf("%w, it has %s, force the load to load the baseline anyway", ErrDatabaseNotEmpty, strings.Join(existing, ", "))
+	}
+
+	return nil
+}
+
+func (b *baselilner) execAndRecord(db Execer, eventType int, statements []Object, record func(db Execer) error) error {
+	err := b.execStatements(db, eventType, statements)
+	if err != nil || record == nil {
+		return err
+	}
+
+	return record(db)
+}
+
+// execStatements executes the statements in order, progress is dispatched before every statement
+func (b *baselilner) execStatements(db Execer, eventType int, statements []Object) error {
+	for i, statement := range statements {
+		b.dispatchProgress(eventType, i+1, len(statements), statement.Type, statement.Name)
+		_, err := db.Exec(statement.SQL)
+		if err != nil {
+			return objectError(statement.Type, statement.Name, fmt.Errorf("SQL Execution Error: %v query: %s", err, statement.SQL))
+		}
+	}
+
+	return nil
+}
+
+// readStatements splits a baseline file into statements, with the object named by the preceding object annotation
+func (b *baselilner) readStatements(fsys fs.FS, filename string) ([]schemaObject, error) {
+	file, err := fsys.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("file opening error %s Error:%v", filename, err)
 
 	}
 	defer file.Close()
 
+	var statements []schemaObject
+	var object schemaObject
 	var statementBuilder strings.Builder
+	flush := func() {
+		object.sql = statementBuilder.String()
+		statementBuilder.Reset()
+
+		// Statements after the first one of an object, like its comments or policies, belong to the same object
+		statements = append(statements, object)
+	}
+
 	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
 	isDelimiterSeparation := false
 	for scanner.Scan() {
 		line := scanner.Text()
+		if header, ok := strings.CutPrefix(line, objectAnnotation+" "); ok {
+			object.objectType, object.name, _ = strings.Cut(header, " ")
+			continue
+		}
+
 		if strings.HasPrefix(strings.TrimSpace(line), "--") || line == "" {
 			continue
 		}
@@ -31,20 +193,61 @@ func (b *baselilner) Load(migrationFilePath string) error {
 			continue
 		}
 
-		if line != closingDelimiter {
-			statementBuilder.WriteString(line + "\n")
+		// The closing delimiter ends the delimited statement, the statements after it end with a semicolon again
+		if isDelimiterSeparation && b.detectStatementEnd(line, isDelimiterSeparation) {
+			isDelimiterSeparation = false
+			flush()
+			continue
 		}
 
+		statementBuilder.WriteString(line + "\n")
 		if b.detectStatementEnd(line, isDelimiterSeparation) {
-			query := statementBuilder.String()
-			statementBuilder.Reset()
-
-			_, err := b.db.Exec(query)
-			if err != nil {
-				return fmt.Errorf("SQL Execution Error: %v query: %s", err, query)
-			}
+			flush()
 		}
 	}
 
-	return scanner.Err()
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read %s, error: %v", filename, err)
+	}
+
+	if strings.TrimSpace(statementBuilder.String()) != "" {
+		return nil, fmt.Errorf("%s ends in an unterminated statement of %s %s", filename, object.objectType, object.name)
+	}
+
+	return statements, nil
+}
+
+// Version returns the migration stamped into the baseline, empty if there is no baseline or it is not stamped
+func (b *baselilner) Version(fsys fs.FS) (string, error) {
+	version, err := b.version(fsys)
+	return version, objectError("", "", err)
+}
+
+func (*baselilner) version(fsys fs.FS) (string, error) {
+	fileName := path.Join(splitBaselineFolder, manifestFileName)
+	file, err := fsys.Open(fileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		fileName = baselineFileName
+		file, err = fsys.Open(fileName)
+	}
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", fmt.Errorf("file opening error %s Error:%v", fileName, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return "", scanner.Err()
+	}
+
+	if version, ok := strings.CutPrefix(scanner.Text(), versionAnnotation); ok {
+		return strings.TrimSpace(version), nil
+	}
+
+	return "", nil
 }
@@ -0,0 +1,92 @@
+package baseliner
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// getSchemaSQLs returns the SQL of the objects of the current schema, in one query when the type has a bulk query
+// Objects missing from the bulk result are queried one by one, parallelRetrieval of them at once
+func (b *baselilner) getSchemaSQLs(queryType string, listedNames, names []string) ([]string, error) {
+	bulk, err := b.getBulkSchemaSQL(queryType)
+	if err != nil {
+		return nil, objectError(queryType, "", err)
+	}
+
+	schemaSQLs := make([]string, len(listedNames))
+	var missing []int
+	for i, listedName := range listedNames {
+		if schemaSQL, ok := bulk[listedName]; ok {
+			schemaSQLs[i] = schemaSQL
+			continue
+		}
+
+		missing = append(missing, i)
+	}
+
+	errs := make([]error, len(listedNames))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < min(max(b.baselineInstruction.parallelRetrieval, 1), len(missing)); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				schemaSQLs[i], errs[i] = b.getSchemaSQL(queryType, listedNames[i])
+			}
+		}()
+	}
+
+	for _, i := range missing {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, objectError(queryType, names[i], err)
+		}
+	}
+
+	return schemaSQLs, nil
+}
+
+// getBulkSchemaSQL returns the SQL of every object of the type by name, nil if the type has no bulk query
+// Objects without SQL are left out, the first definition is kept for overloaded names, as by the per object query
+func (b *baselilner) getBulkSchemaSQL(queryType string) (map[string]string, error) {
+	query, ok := b.baselineInstruction.schemaRetrievalQueries[queryType]
+	if !ok || query.bulkQuery == "" {
+		return nil, nil
+	}
+
+	bulkQuery := query.bulkQuery
+	if query.dbNameShouldBePassed {
+		bulkQuery = fmt.Sprintf(bulkQuery, b.databaseName)
+	}
+
+	rows, err := b.db.Query(bulkQuery)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get schema data (%s), error: %v", bulkQuery, err)
+	}
+	defer rows.Close()
+
+	result := make(map[string]string)
+	for rows.Next() {
+		var name string
+		var schemaSQL sql.NullString
+		err := rows.Scan(&name, &schemaSQL)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get schema data (%s), error: %v", bulkQuery, err)
+		}
+
+		if _, ok := result[name]; ok || !schemaSQL.Valid {
+			continue
+		}
+
+		result[name] = schemaSQL.String
+	}
+
+	return result, rows.Err()
+}
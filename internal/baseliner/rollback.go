@@ -0,0 +1,127 @@
+package baseliner
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/olbrichattila/godbmigrator/config"
+)
+
+// dropKeywords are the keywords of the DROP statements of the object types the rollback drops
+// Comments, policies, grants and data go with their table, schemas and extensions may be shared, so they are kept
+var dropKeywords = map[string]string{
+	queryTypeTriggers:      "TRIGGER",
+	queryTypeProcedures:    "PROCEDURE",
+	queryTypeFunctions:     "FUNCTION",
+	queryTypeMaterialViews: "MATERIALIZED VIEW",
+	queryTypeViews:         "VIEW",
+	queryTypeIndex:         "INDEX",
+	queryTypeTables:        "TABLE",
+	queryTypeSequences:     "SEQUENCE",
+	queryTypeDomains:       "DOMAIN",
+	queryTypeTypes:         "TYPE",
+}
+
+// saveRollback writes the rollback of the saved objects, dropping them in reverse creation order
+func (b *baselilner) saveRollback(migrationFilePath string, statements []schemaObject) error {
+	var rollback strings.Builder
+	dropped := make(map[string]bool)
+	for i := len(statements) - 1; i >= 0; i-- {
+		object := statements[i]
+		if dropped[objectKey(object)] {
+			continue
+		}
+
+		dropSQL := b.dropStatement(object)
+		if dropSQL == "" {
+			continue
+		}
+
+		dropped[objectKey(object)] = true
+		rollback.WriteString(fmt.Sprintf("%s %s %s\n%s;\n", objectAnnotation, object.objectType, object.name, dropSQL))
+	}
+
+	err := os.WriteFile(path.Join(migrationFilePath, rollbackFileName), []byte(rollback.String()), 0o644)
+	if err != nil {
+		return fmt.Errorf("cannot save %s, error: %v", rollbackFileName, err)
+	}
+
+	return nil
+}
+
+// dropStatement returns the statement dropping the object, empty if the object is not dropped
+// Names of constraints, and of triggers on PostgreSQL, are <table>.<name> and are dropped on their table
+func (b *baselilner) dropStatement(object schemaObject) string {
+	if object.objectType == queryTypeConstraints && b.baselineInstruction.dropConstraintSQL != "" {
+		table, name := splitTableName(object.name)
+		return fmt.Sprintf(b.baselineInstruction.dropConstraintSQL, b.quoteName(table), b.quoteName(name))
+	}
+
+	keyword, ok := dropKeywords[object.objectType]
+	if !ok {
+		return ""
+	}
+
+	if object.objectType == queryTypeTriggers && b.baselineInstruction.dropTriggerOnTable {
+		table, name := splitTableName(object.name)
+		return "DROP TRIGGER IF EXISTS " + b.quoteName(name) + " ON " + b.quoteName(table)
+	}
+
+	return "DROP " + keyword + " IF EXISTS " + b.quoteName(object.name)
+}
+
+// quoteName quotes every part of a schema qualified name
+func (b *baselilner) quoteName(name string) string {
+	quote := b.baselineInstruction.identifierQuote
+	parts := strings.Split(name, ".")
+	for i, part := range parts {
+		parts[i] = quote + strings.ReplaceAll(part, quote, quote+quote) + quote
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// splitTableName splits <table>.<name> at the last dot, the table may be schema qualified
+func splitTableName(name string) (string, string) {
+	i := strings.LastIndex(name, ".")
+	if i < 0 {
+		return "", name
+	}
+
+	return name[:i], name[i+1:]
+}
+
+// Rollback executes the rollback saved with the baseline, in one transaction when the dialect has transactional DDL
+func (b *baselilner) Rollback(fsys fs.FS) error {
+	return objectError("", "", b.rollback(fsys))
+}
+
+func (b *baselilner) rollback(fsys fs.FS) error {
+	err := b.loadInstructions()
+	if err != nil {
+		return err
+	}
+
+	_, err = fs.Stat(fsys, rollbackFileName)
+	if errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("%s does not exist, save the baseline again to create it", rollbackFileName)
+	}
+
+	statements, err := b.readStatements(fsys, rollbackFileName)
+	if err != nil {
+		return err
+	}
+
+	var objects []Object
+	for _, statement := range statements {
+		if b.isIncluded(statement.objectType, statement.name) {
+			objects = append(objects, Object{Type: statement.objectType, Name: statement.name, SQL: statement.sql})
+		}
+	}
+
+	return b.execInTransaction(config.BaselineRollingBackObject, objects, nil)
+}
@@ -1,58 +1,192 @@
 package baseliner
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"io/fs"
 	"os"
+	"path"
 	"strings"
+
+	"github.com/olbrichattila/godbmigrator/config"
 )
 
-func (b *baselilner) Save(migrationFilePath string) error {
-	baselineInstruction, err := b.getEngineSpecificInstructions()
+// SaveOptions of Save, Version is stamped as the last migration the baseline covers
+// The rows of the tables matching DataTables are saved after the schema
+// Split writes one file per object into the baseline folder, with a manifest of the load order
+type SaveOptions struct {
+	Version    string
+	DataTables []string
+	Split      bool
+}
+
+// Save writes the schema into baseline.sql, or into the baseline folder when it is split
+// The other layout is removed, so Load cannot read a stale baseline, baseline-rollback.sql drops the saved objects
+func (b *baselilner) Save(migrationFilePath string, options SaveOptions) error {
+	return objectError("", "", b.save(migrationFilePath, options))
+}
+
+func (b *baselilner) save(migrationFilePath string, options SaveOptions) error {
+	err := b.loadInstructions()
 	if err != nil {
 		return err
 	}
-	b.baselineInstruction = *baselineInstruction
 
-	filename := migrationFilePath + "/baseline.sql"
+	if options.Split {
+		err = os.Remove(path.Join(migrationFilePath, baselineFileName))
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("cannot remove %s, error: %v", baselineFileName, err)
+		}
+
+		saved, err := b.saveSplit(path.Join(migrationFilePath, splitBaselineFolder), options)
+		if err != nil {
+			return err
+		}
+
+		return b.saveRollback(migrationFilePath, saved)
+	}
+
+	err = b.removeSplit(path.Join(migrationFilePath, splitBaselineFolder))
+	if err != nil {
+		return err
+	}
+
+	saved, err := b.saveFile(path.Join(migrationFilePath, baselineFileName), options.Version, options.DataTables)
+	if err != nil {
+		return err
+	}
+
+	return b.saveRollback(migrationFilePath, saved)
+}
+
+// SaveSnapshot writes the schema into fileName in the baseline format, used as reference of drift detection
+func (b *baselilner) SaveSnapshot(fileName string) error {
+	return objectError("", "", b.saveSnapshot(fileName))
+}
+
+func (b *baselilner) saveSnapshot(fileName string) error {
+	err := b.loadInstructions()
+	if err != nil {
+		return err
+	}
+
+	_, err = b.saveFile(fileName, "", nil)
+	return err
+}
+
+// saveFile writes the baseline into filename, the type and name of the saved objects are returned in creation order
+func (b *baselilner) saveFile(filename, version string, dataTables []string) ([]schemaObject, error) {
 	file, err := os.Create(filename)
 	if err != nil {
-		return fmt.Errorf("error opening file: %v", err)
+		return nil, fmt.Errorf("error opening file: %v", err)
 	}
 
 	defer file.Close()
 
-	err = b.GetSchemaData(func(schemaDef string, useDelimiter bool) error {
-		schemaAppend := ";\n"
-		if useDelimiter {
-			schemaAppend = "\n"
-			_, err := file.WriteString(openingDelimiter + "\n")
-			if err != nil {
-				return fmt.Errorf("cannot save baseline when string opening delimiter, error: %v", err)
-			}
+	if version != "" {
+		_, err = file.WriteString(versionAnnotation + " " + version + "\n")
+		if err != nil {
+			return nil, fmt.Errorf("cannot save baseline version, error: %v", err)
 		}
+	}
+
+	var saved []schemaObject
+	err = b.walkBaseline(dataTables, func(object schemaObject) error {
+		saved = append(saved, schemaObject{objectType: object.objectType, name: object.name})
+		return b.writeObject(file, object)
+	})
 
-		_, err := file.WriteString(schemaDef + schemaAppend)
+	return saved, err
+}
+
+// walkBaseline calls back with the objects of the schema in creation order, followed by the rows of the data tables
+// Progress is dispatched before every object, the rows of a table count as one object
+func (b *baselilner) walkBaseline(dataTables []string, callback func(schemaObject) error) error {
+	objects, err := b.schemaObjects()
+	if err != nil {
+		return err
+	}
+
+	var tables []string
+	for _, object := range objects {
+		if object.objectType == queryTypeTables {
+			tables = append(tables, object.name)
+		}
+	}
+
+	tables, err = matchingTables(tables, dataTables)
+	if err != nil {
+		return err
+	}
+
+	total := len(objects) + len(tables)
+	for i, object := range objects {
+		b.dispatchProgress(config.BaselineSavingObject, i+1, total, object.objectType, object.name)
+		err = callback(object)
 		if err != nil {
-			return fmt.Errorf("cannot save baseline schema sql, error: %v", err)
+			return objectError(object.objectType, object.name, err)
 		}
+	}
 
-		if useDelimiter {
-			_, err := file.WriteString(closingDelimiter + "\n")
-			if err != nil {
-				return fmt.Errorf("cannot save baseline when string closing delimiter, error: %v", err)
-			}
+	for i, table := range tables {
+		b.dispatchProgress(config.BaselineSavingObject, len(objects)+i+1, total, queryTypeData, table)
+		err = b.walkInserts(table, callback)
+		if err != nil {
+			return objectError(queryTypeData, table, err)
 		}
+	}
 
-		return nil
-	})
+	return nil
+}
+
+// dispatchProgress sends the position of the object as "<index>/<total> <object type> <name>"
+func (b *baselilner) dispatchProgress(eventType, index, total int, objectType, name string) {
+	if b.msg != nil {
+		b.msg.Dispatch(eventType, fmt.Sprintf("%d/%d %s %s", index, total, objectType, name))
+	}
+}
 
+// writeObject writes the object annotation and the SQL of the object, with delimiters when the SQL contains semicolons
+func (b *baselilner) writeObject(file io.StringWriter, object schemaObject) error {
+	_, err := file.WriteString(fmt.Sprintf("%s %s %s\n", objectAnnotation, object.objectType, object.name))
 	if err != nil {
-		return err
+		return fmt.Errorf("cannot save baseline object header, error: %v", err)
+	}
+
+	useDelimiter := b.useDelimiter(object.objectType)
+	schemaAppend := ";\n"
+	if useDelimiter {
+		schemaAppend = "\n"
+		_, err := file.WriteString(openingDelimiter + "\n")
+		if err != nil {
+			return fmt.Errorf("cannot save baseline when string opening delimiter, error: %v", err)
+		}
+	}
+
+	_, err = file.WriteString(object.sql + schemaAppend)
+	if err != nil {
+		return fmt.Errorf("cannot save baseline schema sql, error: %v", err)
+	}
+
+	if useDelimiter {
+		_, err := file.WriteString(closingDelimiter + "\n")
+		if err != nil {
+			return fmt.Errorf("cannot save baseline when string closing delimiter, error: %v", err)
+		}
 	}
 
 	return nil
+}
 
+func (b *baselilner) loadInstructions() error {
+	baselineInstruction, err := b.getEngineSpecificInstructions()
+	if err != nil {
+		return err
+	}
+	b.baselineInstruction = *baselineInstruction
+
+	return nil
 }
 
 func (*baselilner) detectStatementEnd(line string, isDelimiterSeparation bool) bool {
@@ -64,25 +198,110 @@ func (*baselilner) detectStatementEnd(line string, isDelimiterSeparation bool) b
 }
 
 func (b *baselilner) GetSchemaData(callback func(string, bool) error) error {
-	databaseName, err := b.getActiveDatabaseName()
+	return b.walkSchemaObjects(func(object schemaObject) error {
+		return callback(object.sql, b.useDelimiter(object.objectType))
+	})
+}
+
+// walkSchemaObjects calls back with every object of the schemas, in the order they can be created
+func (b *baselilner) walkSchemaObjects(callback func(schemaObject) error) error {
+	objects, err := b.schemaObjects()
 	if err != nil {
 		return err
 	}
-	b.databaseName = databaseName
 
-	for _, pType := range b.baselineInstruction.execute {
-		tables, err := b.getInformationSchemaList(pType)
+	for _, object := range objects {
+		err = callback(object)
 		if err != nil {
 			return err
 		}
+	}
+
+	return nil
+}
+
+// schemaObjects returns every object of the schemas, in the order they can be created
+func (b *baselilner) schemaObjects() ([]schemaObject, error) {
+	schemas, err := b.getSchemaNames()
+	if err != nil {
+		return nil, err
+	}
 
-		for _, tableName := range tables {
-			schemaSQL, err := b.getSchemaSQL(pType, tableName)
+	var objects []schemaObject
+	err = b.listSchemaObjects(schemas, func(object schemaObject) error {
+		objects = append(objects, object)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return b.sortByDependencies(objects, schemas)
+}
+
+// listSchemaObjects calls back with every object of the schemas, grouped by type in the order of execute
+func (b *baselilner) listSchemaObjects(schemas []string, callback func(schemaObject) error) error {
+	var err error
+
+	if len(b.schemas) > 0 {
+		for _, schema := range schemas {
+			var createSchemaSQL string
+			err = b.db.QueryRow(b.baselineInstruction.createSchemaSQL, schema).Scan(&createSchemaSQL)
+			if err != nil {
+				return objectError(queryTypeSchema, schema, fmt.Errorf("cannot get schema creation SQL, error: %v", err))
+			}
+
+			err = callback(schemaObject{objectType: queryTypeSchema, name: schema, sql: createSchemaSQL})
 			if err != nil {
 				return err
 			}
+		}
+	}
+
+	return b.walkObjectNames(schemas, func(objectType string, listedNames, names []string) error {
+		schemaSQLs, err := b.getSchemaSQLs(objectType, listedNames, names)
+		if err != nil {
+			return err
+		}
+
+		for i, name := range names {
+			err = callback(schemaObject{objectType: objectType, name: name, sql: schemaSQLs[i]})
+			if err != nil {
+				return objectError(objectType, name, err)
+			}
+		}
+
+		return nil
+	})
+}
 
-			err = callback(schemaSQL, b.useDelimiter(pType))
+// walkObjectNames calls back with the objects passing the filter, one batch per object type and schema
+// listedNames are the names the lister returned, names are the names in the baseline
+func (b *baselilner) walkObjectNames(schemas []string, callback func(objectType string, listedNames, names []string) error) error {
+	for _, pType := range b.baselineInstruction.execute {
+		for _, schema := range schemas {
+			b.databaseName = schema
+			tables, err := b.getInformationSchemaList(pType)
+			if err != nil {
+				return objectError(pType, "", err)
+			}
+
+			var listedNames, names []string
+			for _, tableName := range tables {
+				name := b.objectName(schema, tableName)
+				if !b.isIncluded(pType, name) {
+					continue
+				}
+
+				listedNames = append(listedNames, tableName)
+				names = append(names, name)
+			}
+
+			if len(names) == 0 {
+				continue
+			}
+
+			err = callback(pType, listedNames, names)
 			if err != nil {
 				return err
 			}
@@ -92,6 +311,45 @@ func (b *baselilner) GetSchemaData(callback func(string, bool) error) error {
 	return nil
 }
 
+// isIncluded tells if the object passes the filter, the rows of a table follow the table
+func (b *baselilner) isIncluded(objectType, name string) bool {
+	if b.filter == nil || objectType == "" {
+		return true
+	}
+
+	if objectType == queryTypeData {
+		objectType = queryTypeTables
+	}
+
+	return b.filter(objectType, name)
+}
+
+// objectName qualifies the name with the schema, when schemas are listed explicitly
+func (b *baselilner) objectName(schema, name string) string {
+	if len(b.schemas) == 0 {
+		return name
+	}
+
+	return schema + "." + name
+}
+
+func (b *baselilner) getSchemaNames() ([]string, error) {
+	if len(b.schemas) == 0 {
+		databaseName, err := b.getActiveDatabaseName()
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{databaseName}, nil
+	}
+
+	if b.baselineInstruction.createSchemaSQL == "" {
+		return nil, fmt.Errorf("multi schema baseline is not supported for this database type")
+	}
+
+	return b.schemas, nil
+}
+
 func (b *baselilner) getInformationSchemaList(queryType string) ([]string, error) {
 	sql, err := b.getListQuery(queryType)
 	if err != nil {
@@ -165,11 +423,13 @@ func (b *baselilner) getSchemaSQL(queryType string, tableName string) (string, e
 }
 
 func (b *baselilner) useDelimiter(typeText string) bool {
-	if typeText == queryTypeTables || typeText == queryTypeIndex {
+	switch typeText {
+	case queryTypeTables, queryTypeIndex, queryTypeSequences, queryTypeSchema, queryTypeConstraints, queryTypeComments,
+		queryTypeExtensions, queryTypeTypes, queryTypeDomains, queryTypePolicies, queryTypeGrants, queryTypeData:
 		return false
+	default:
+		return true
 	}
-
-	return true
 }
 
 func (b *baselilner) getActiveDatabaseName() (string, error) {
@@ -180,8 +440,9 @@ func (b *baselilner) getActiveDatabaseName() (string, error) {
 	var dbName string
 	err := b.db.QueryRow(b.baselineInstruction.activeDatabaseSQL).Scan(&dbName)
 	if err != nil {
-		log.Fatal(err)
+		return "", fmt.Errorf("cannot get active database name, error: %v", err)
 	}
+
 	return dbName, nil
 }
 
@@ -0,0 +1,353 @@
+package baseliner
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+)
+
+// Constraint types
+const (
+	ConstraintPrimaryKey = "PRIMARY KEY"
+	ConstraintUnique     = "UNIQUE"
+	ConstraintForeignKey = "FOREIGN KEY"
+	ConstraintCheck      = "CHECK"
+	ConstraintExclude    = "EXCLUDE"
+)
+
+// Schema is the typed model of the database returned by Inspect, objects are in the order they are saved into the baseline
+type Schema struct {
+	Tables   []Table
+	Views    []View
+	Routines []Routine
+	Triggers []Trigger
+}
+
+// Table with its columns in ordinal position, its constraints and the indexes not created by a constraint
+type Table struct {
+	Name        string
+	Comment     string
+	Columns     []Column
+	Constraints []Constraint
+	Indexes     []Index
+}
+
+// Constraint of a table, Name is empty when the database does not name it
+// ReferencedTable and ReferencedColumns are set for foreign keys, Check for check constraints
+type Constraint struct {
+	Name              string
+	Type              string
+	Columns           []string
+	ReferencedTable   string
+	ReferencedColumns []string
+	Check             string
+}
+
+// Index of a table, Columns are column names or expressions
+type Index struct {
+	Name    string
+	Unique  bool
+	Columns []string
+}
+
+// View is a view or a materialized view and the SQL creating it
+type View struct {
+	Name         string
+	Materialized bool
+	SQL          string
+}
+
+// Routine is a function or a procedure, Type is ObjectTypeFunction or ObjectTypeProcedure
+type Routine struct {
+	Name string
+	Type string
+	SQL  string
+}
+
+// Trigger and the table it belongs to
+type Trigger struct {
+	Name  string
+	Table string
+	SQL   string
+}
+
+// inspectQueries return the details of every table of a schema, one row per column, ordered by table and position
+type inspectQueries struct {
+	// columns returns table, name, type, not null, default, primary key, extra and comment
+	columns string
+	// tableComments returns table and comment of the commented tables
+	tableComments string
+	// constraints returns table, a key grouping the rows of a constraint, name, type, column,
+	// then referenced schema, table and column of foreign keys and the clause of checks
+	constraints string
+	// indexes returns table, name, unique and column of the indexes not created by a constraint
+	indexes string
+	// splitIndexColumns tells the column of indexes is the list of every column as text, like [a, b]
+	splitIndexColumns bool
+	// triggers returns table and name of the triggers
+	triggers string
+}
+
+// Inspect returns the typed model of the schema, objects left out by the filter are left out of the model
+func (b *baselilner) Inspect() (*Schema, error) {
+	schema, err := b.inspect()
+	return schema, objectError("", "", err)
+}
+
+func (b *baselilner) inspect() (*Schema, error) {
+	objects, err := b.objects()
+	if err != nil {
+		return nil, err
+	}
+
+	return b.inspectObjects(objects)
+}
+
+// inspectObjects builds the model of the objects, reading the details of their tables
+func (b *baselilner) inspectObjects(objects []Object) (*Schema, error) {
+	if b.baselineInstruction.inspectQueries.columns == "" {
+		return nil, fmt.Errorf("schema inspection is not supported for %s database type", b.dialect)
+	}
+
+	schemaNames, err := b.getSchemaNames()
+	if err != nil {
+		return nil, err
+	}
+
+	tables := make(map[string]*Table)
+	triggerTables := make(map[string]string)
+	for _, schemaName := range schemaNames {
+		err = b.inspectTables(schemaName, tables, triggerTables)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	schema := &Schema{}
+	for _, object := range objects {
+		switch object.Type {
+		case queryTypeTables:
+			table := tableOf(tables, object.Name)
+			schema.Tables = append(schema.Tables, *table)
+		case queryTypeViews, queryTypeMaterialViews:
+			schema.Views = append(schema.Views, View{Name: object.Name, Materialized: object.Type == queryTypeMaterialViews, SQL: object.SQL})
+		case queryTypeFunctions, queryTypeProcedures:
+			schema.Routines = append(schema.Routines, Routine{Name: object.Name, Type: object.Type, SQL: object.SQL})
+		case queryTypeTriggers:
+			schema.Triggers = append(schema.Triggers, Trigger{Name: object.Name, Table: triggerTables[object.Name], SQL: object.SQL})
+		}
+	}
+
+	return schema, nil
+}
+
+// inspectTables reads the columns, constraints, indexes and triggers of the tables of the schema
+func (b *baselilner) inspectTables(schemaName string, tables map[string]*Table, triggerTables map[string]string) error {
+	queries := b.baselineInstruction.inspectQueries
+	err := b.inspectRows(queries.columns, schemaName, func(rows *sql.Rows) error {
+		var tableName string
+		var column Column
+		var comment sql.NullString
+		err := rows.Scan(&tableName, &column.Name, &column.Type, &column.NotNull, &column.Default, &column.PrimaryKey, &column.Extra, &comment)
+		if err != nil {
+			return err
+		}
+
+		column.Comment = comment.String
+
+		table := tableOf(tables, b.objectName(schemaName, tableName))
+		table.Columns = append(table.Columns, column)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot inspect columns, error: %v", err)
+	}
+
+	err = b.inspectRows(queries.tableComments, schemaName, func(rows *sql.Rows) error {
+		var tableName, comment string
+		err := rows.Scan(&tableName, &comment)
+		if err != nil {
+			return err
+		}
+
+		tableOf(tables, b.objectName(schemaName, tableName)).Comment = comment
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot inspect table comments, error: %v", err)
+	}
+
+	var lastKey string
+	var skipped bool
+	err = b.inspectRows(queries.constraints, schemaName, func(rows *sql.Rows) error {
+		var tableName, key, constraintType string
+		var name, column, referencedSchema, referencedTable, referencedColumn, check sql.NullString
+		err := rows.Scan(&tableName, &key, &name, &constraintType, &column, &referencedSchema, &referencedTable, &referencedColumn, &check)
+		if err != nil {
+			return err
+		}
+
+		table := tableOf(tables, b.objectName(schemaName, tableName))
+		key = table.Name + "\x00" + key
+		if key != lastKey {
+			lastKey = key
+			skipped = name.Valid && !b.isIncluded(queryTypeConstraints, table.Name+"."+name.String)
+			if skipped {
+				return nil
+			}
+
+			constraint := Constraint{Name: name.String, Type: constraintType, Check: check.String}
+			if referencedTable.Valid {
+				if !referencedSchema.Valid {
+					referencedSchema.String = schemaName
+				}
+				constraint.ReferencedTable = b.objectName(referencedSchema.String, referencedTable.String)
+			}
+			table.Constraints = append(table.Constraints, constraint)
+		}
+
+		if skipped {
+			return nil
+		}
+
+		constraint := &table.Constraints[len(table.Constraints)-1]
+		if column.Valid {
+			constraint.Columns = append(constraint.Columns, column.String)
+		}
+		if referencedColumn.Valid {
+			constraint.ReferencedColumns = append(constraint.ReferencedColumns, referencedColumn.String)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot inspect constraints, error: %v", err)
+	}
+
+	lastKey = ""
+	err = b.inspectRows(queries.indexes, schemaName, func(rows *sql.Rows) error {
+		var tableName, name string
+		var unique bool
+		var column sql.NullString
+		err := rows.Scan(&tableName, &name, &unique, &column)
+		if err != nil {
+			return err
+		}
+
+		table := tableOf(tables, b.objectName(schemaName, tableName))
+		if key := table.Name + "\x00" + name; key != lastKey {
+			lastKey = key
+			skipped = !b.isIncluded(queryTypeIndex, b.objectName(schemaName, name))
+			if skipped {
+				return nil
+			}
+
+			table.Indexes = append(table.Indexes, Index{Name: b.objectName(schemaName, name), Unique: unique})
+		}
+
+		if skipped {
+			return nil
+		}
+
+		index := &table.Indexes[len(table.Indexes)-1]
+		switch {
+		case !column.Valid:
+		case queries.splitIndexColumns:
+			index.Columns = append(index.Columns, splitList(column.String)...)
+		default:
+			index.Columns = append(index.Columns, column.String)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot inspect indexes, error: %v", err)
+	}
+
+	err = b.inspectRows(queries.triggers, schemaName, func(rows *sql.Rows) error {
+		var tableName, name string
+		err := rows.Scan(&tableName, &name)
+		if err != nil {
+			return err
+		}
+
+		triggerTables[b.objectName(schemaName, name)] = b.objectName(schemaName, tableName)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot inspect triggers, error: %v", err)
+	}
+
+	return nil
+}
+
+// inspectRows calls back with every row of the query, the schema is passed when the dialect has schemas
+func (b *baselilner) inspectRows(query, schemaName string, callback func(*sql.Rows) error) error {
+	if query == "" {
+		return nil
+	}
+
+	sqlParams := make([]any, 0)
+	if schemaName != "" {
+		sqlParams = append(sqlParams, schemaName)
+	}
+
+	rows, err := b.db.Query(query, sqlParams...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = callback(rows)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// tableOf returns the table of the name, created on first use
+func tableOf(tables map[string]*Table, name string) *Table {
+	table, ok := tables[name]
+	if !ok {
+		table = &Table{Name: name}
+		tables[name] = table
+	}
+
+	return table
+}
+
+// splitList splits a list rendered as text, like [a, (b + c)], at the commas outside of parentheses and quotes
+func splitList(list string) []string {
+	list = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(list), "["), "]")
+
+	var items []string
+	var depth int
+	var quote rune
+	start := 0
+	for i, r := range list {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ',' && depth == 0:
+			items = append(items, strings.TrimSpace(list[start:i]))
+			start = i + 1
+		}
+	}
+
+	if item := strings.TrimSpace(list[start:]); item != "" {
+		items = append(items, item)
+	}
+
+	return items
+}
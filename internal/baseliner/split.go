@@ -0,0 +1,195 @@
+package baseliner
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+)
+
+const (
+	// splitBaselineFolder receives one file per object, when the baseline is split
+	splitBaselineFolder = "baseline"
+	// manifestFileName lists the object files of a split baseline in load order
+	manifestFileName = "manifest.txt"
+)
+
+// objectFolders are the folders of the object types in a split baseline
+var objectFolders = map[string]string{
+	queryTypeSchema:        "schemas",
+	queryTypeExtensions:    "extensions",
+	queryTypeTypes:         "types",
+	queryTypeDomains:       "domains",
+	queryTypeSequences:     "sequences",
+	queryTypeTables:        "tables",
+	queryTypeConstraints:   "constraints",
+	queryTypeIndex:         "indexes",
+	queryTypeComments:      "comments",
+	queryTypeViews:         "views",
+	queryTypeMaterialViews: "materialized_views",
+	queryTypeFunctions:     "functions",
+	queryTypeProcedures:    "procedures",
+	queryTypeTriggers:      "triggers",
+	queryTypePolicies:      "policies",
+	queryTypeGrants:        "grants",
+	queryTypeData:          "data",
+}
+
+// saveSplit writes every object into <type folder>/<name>.sql, and their order into the manifest
+// The type and name of the saved objects are returned in creation order
+func (b *baselilner) saveSplit(folder string, options SaveOptions) ([]schemaObject, error) {
+	var manifest []string
+	var saved []schemaObject
+	files := make(map[string]*strings.Builder)
+	objectFiles := make(map[string]string)
+	takenFileNames := make(map[string]bool)
+	err := b.walkBaseline(options.DataTables, func(object schemaObject) error {
+		saved = append(saved, schemaObject{objectType: object.objectType, name: object.name})
+		fileName, ok := objectFiles[objectKey(object)]
+		if !ok {
+			fileName = uniqueFileName(objectFileName(object), takenFileNames)
+			objectFiles[objectKey(object)] = fileName
+			files[fileName] = &strings.Builder{}
+			manifest = append(manifest, fileName)
+		}
+
+		file := files[fileName]
+
+		object.sql = normalizeWhitespace(object.sql)
+		return b.writeObject(file, object)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.removeSplit(folder)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fileName := range manifest {
+		filePath := path.Join(folder, fileName)
+		err = os.MkdirAll(path.Dir(filePath), 0o755)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create baseline folder %s, error: %v", path.Dir(filePath), err)
+		}
+
+		err = os.WriteFile(filePath, []byte(files[fileName].String()), 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("cannot save baseline file %s, error: %v", filePath, err)
+		}
+	}
+
+	var content strings.Builder
+	if options.Version != "" {
+		content.WriteString(versionAnnotation + " " + options.Version + "\n")
+	}
+
+	for _, fileName := range manifest {
+		content.WriteString(fileName + "\n")
+	}
+
+	err = os.WriteFile(path.Join(folder, manifestFileName), []byte(content.String()), 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot save baseline manifest, error: %v", err)
+	}
+
+	return saved, nil
+}
+
+// removeSplit deletes a split baseline, files of dropped objects must not remain
+func (*baselilner) removeSplit(folder string) error {
+	err := os.RemoveAll(folder)
+	if err != nil {
+		return fmt.Errorf("cannot remove baseline folder %s, error: %v", folder, err)
+	}
+
+	return nil
+}
+
+// readBaseline reads the statements of the split baseline if it has a manifest, otherwise of baseline.sql
+func (b *baselilner) readBaseline(fsys fs.FS) ([]schemaObject, error) {
+	fileNames, err := b.manifest(fsys)
+	if errors.Is(err, fs.ErrNotExist) {
+		return b.readStatements(fsys, baselineFileName)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var statements []schemaObject
+	for _, fileName := range fileNames {
+		fileStatements, err := b.readStatements(fsys, path.Join(splitBaselineFolder, fileName))
+		if err != nil {
+			return nil, err
+		}
+
+		statements = append(statements, fileStatements...)
+	}
+
+	return statements, nil
+}
+
+// manifest returns the object files of the split baseline in load order
+func (*baselilner) manifest(fsys fs.FS) ([]string, error) {
+	file, err := fsys.Open(path.Join(splitBaselineFolder, manifestFileName))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var fileNames []string
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "--") {
+			continue
+		}
+
+		fileNames = append(fileNames, line)
+	}
+
+	return fileNames, scanner.Err()
+}
+
+// objectFileName is the path of the object in the split baseline, characters unsafe in file names are replaced
+func objectFileName(object schemaObject) string {
+	folder, ok := objectFolders[object.objectType]
+	if !ok {
+		folder = object.objectType
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '.' {
+			return r
+		}
+
+		return '_'
+	}, object.name)
+
+	return folder + "/" + name + ".sql"
+}
+
+// uniqueFileName numbers the file name when another object has it already, names can sanitize to the same file name
+// The names are compared case-insensitively, as case-insensitive file systems cannot keep them apart
+func uniqueFileName(fileName string, taken map[string]bool) string {
+	baseName := strings.TrimSuffix(fileName, ".sql")
+	for i := 2; taken[strings.ToLower(fileName)]; i++ {
+		fileName = fmt.Sprintf("%s_%d.sql", baseName, i)
+	}
+
+	taken[strings.ToLower(fileName)] = true
+
+	return fileName
+}
+
+// normalizeWhitespace converts line endings and trims the statement, so only real changes show in diffs
+// Lines inside the statement are kept as they are, they may be part of a literal or a routine body
+func normalizeWhitespace(sql string) string {
+	return strings.TrimSpace(strings.ReplaceAll(sql, "\r\n", "\n"))
+}
@@ -0,0 +1,721 @@
+package baseliner
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Canonical column types, the translation maps the types of the source dialect to them and them to the target dialect
+const (
+	typeInteger     = "integer"
+	typeBigInt      = "bigint"
+	typeSmallInt    = "smallint"
+	typeDecimal     = "decimal"
+	typeReal        = "real"
+	typeDouble      = "double"
+	typeBoolean     = "boolean"
+	typeVarchar     = "varchar"
+	typeChar        = "char"
+	typeText        = "text"
+	typeBlob        = "blob"
+	typeDate        = "date"
+	typeTime        = "time"
+	typeTimestamp   = "timestamp"
+	typeTimestampTZ = "timestamptz"
+	typeJSON        = "json"
+	typeUUID        = "uuid"
+)
+
+// typeAliases are the type names of every dialect, without their size, and their canonical type
+var typeAliases = map[string]string{
+	"int": typeInteger, "integer": typeInteger, "int4": typeInteger, "mediumint": typeInteger, "serial": typeInteger,
+	"bigint": typeBigInt, "int8": typeBigInt, "bigserial": typeBigInt,
+	"smallint": typeSmallInt, "int2": typeSmallInt, "tinyint": typeSmallInt, "smallserial": typeSmallInt,
+	"decimal": typeDecimal, "numeric": typeDecimal,
+	"real": typeReal, "float4": typeReal, "float": typeReal,
+	"double": typeDouble, "double precision": typeDouble, "float8": typeDouble,
+	"boolean": typeBoolean, "bool": typeBoolean,
+	"varchar": typeVarchar, "character varying": typeVarchar, "nvarchar": typeVarchar, "varchar2": typeVarchar,
+	"char": typeChar, "character": typeChar, "nchar": typeChar, "bpchar": typeChar,
+	"text": typeText, "clob": typeText, "tinytext": typeText, "mediumtext": typeText, "longtext": typeText, "string": typeText,
+	"blob": typeBlob, "bytea": typeBlob, "binary": typeBlob, "varbinary": typeBlob, "tinyblob": typeBlob, "mediumblob": typeBlob, "longblob": typeBlob,
+	"date": typeDate,
+	"time": typeTime, "time without time zone": typeTime,
+	"datetime": typeTimestamp, "timestamp": typeTimestamp, "timestamp without time zone": typeTimestamp,
+	"timestamptz": typeTimestampTZ, "timestamp with time zone": typeTimestampTZ,
+	"json": typeJSON, "jsonb": typeJSON,
+	"uuid": typeUUID,
+}
+
+var (
+	// columnTypeRegex splits a type like varchar(255) or timestamp(6) with time zone into name, size and suffix
+	columnTypeRegex = regexp.MustCompile(`^([a-z][a-z0-9_ ]*)(?:\(([^)]*)\))?([a-z ]*)$`)
+	// materializedRegex turns a materialized view into a view
+	materializedRegex = regexp.MustCompile(`(?i)\bMATERIALIZED\s+VIEW\b`)
+	// castRegex matches the PostgreSQL cast of a default, like 'a'::character varying
+	castRegex = regexp.MustCompile(`^(.*)::[a-z_ ]+(?:\([0-9, ]*\))?(?:\[\])?$`)
+	// castFunctionRegex matches the DuckDB cast of a default, like CAST('f' AS BOOLEAN)
+	castFunctionRegex = regexp.MustCompile(`(?i)^CAST\((.*) AS [a-z_ ]+(?:\([0-9, ]*\))?(?:\[\])?\)$`)
+	numberRegex       = regexp.MustCompile(`^-?[0-9]+(?:\.[0-9]+)?$`)
+	stringRegex       = regexp.MustCompile(`^'(?:[^']|'')*'$`)
+	identifierRegex   = regexp.MustCompile("^[`\"\\[]?[A-Za-z_][A-Za-z0-9_$]*[`\"\\]]?$")
+)
+
+// translationRules describe a dialect as the source and the target of a translated baseline
+type translationRules struct {
+	// types are the type names of the canonical types
+	types map[string]string
+	// autoIncrement is appended to an auto incremented column
+	autoIncrement string
+	// inlinePrimaryKey tells that an auto incremented column has to be the inline primary key of its table
+	inlinePrimaryKey bool
+	// sequenceDefault tells that an auto incremented column defaults to the next value of a sequence of its own
+	sequenceDefault bool
+	// varcharLength is the length of a varchar without one, empty when the length is optional
+	varcharLength string
+	// materializedViews tells if the dialect has materialized views
+	materializedViews bool
+	// booleans are the false and true literals
+	booleans [2]string
+	// currentDate is the default of the current date
+	currentDate string
+	// commentOn tells that comments are COMMENT ON statements, otherwise they are part of the table when inlineComments is set
+	commentOn      bool
+	inlineComments bool
+	// alterForeignKeys tells that a foreign key can be added to an existing table
+	alterForeignKeys bool
+	// forwardForeignKeys tells that a table can refer to a table created after it
+	forwardForeignKeys bool
+	// indexPrefixTypes are the canonical types which need a prefix length to be indexed
+	indexPrefixTypes []string
+	// expressionDefaultTypes are the canonical types whose default has to be an expression in parentheses
+	expressionDefaultTypes []string
+	// rowidPrimaryKey tells that the INTEGER primary key of the source is auto incremented, as it is the rowid
+	rowidPrimaryKey bool
+	// typeAffinity tells that unknown source types are mapped by the affinity rules of SQLite
+	typeAffinity bool
+	// unquotedDefaults tells that the catalog of the source returns literal defaults without quotes
+	unquotedDefaults bool
+}
+
+// TranslationIssue is a construct which could not be translated, or which was copied unchanged and needs a review
+type TranslationIssue struct {
+	ObjectType string
+	Name       string
+	Message    string
+}
+
+// columnType is a canonical type and its size, like 10,2
+type columnType struct {
+	name string
+	size string
+}
+
+// translator collects the translated objects and the issues of a translation
+type translator struct {
+	source, target      *baselilner
+	sourceRules, rules  translationRules
+	dialect             string
+	objects             []Object
+	issues              []TranslationIssue
+	sequences           map[string]bool
+	indexNames          map[string]string
+	created             map[string]bool
+	deferredForeignKeys []Object
+}
+
+// SaveTranslated writes the schema translated into the target dialect into baseline.sql, with its rollback
+// The split layout and data tables of the options are not used, the constructs which cannot be translated are returned
+func (b *baselilner) SaveTranslated(migrationFilePath, targetDialect string, options SaveOptions) ([]TranslationIssue, error) {
+	issues, err := b.saveTranslated(migrationFilePath, targetDialect, options)
+	return issues, objectError("", "", err)
+}
+
+func (b *baselilner) saveTranslated(migrationFilePath, targetDialect string, options SaveOptions) ([]TranslationIssue, error) {
+	objects, issues, err := b.translate(targetDialect)
+	if err != nil {
+		return nil, err
+	}
+
+	target, err := newTarget(targetDialect)
+	if err != nil {
+		return nil, err
+	}
+
+	err = target.removeSplit(path.Join(migrationFilePath, splitBaselineFolder))
+	if err != nil {
+		return nil, err
+	}
+
+	var content strings.Builder
+	if options.Version != "" {
+		content.WriteString(versionAnnotation + " " + options.Version + "\n")
+	}
+
+	saved := make([]schemaObject, len(objects))
+	for i, object := range objects {
+		saved[i] = schemaObject{objectType: object.Type, name: object.Name, sql: object.SQL}
+		err = target.writeObject(&content, saved[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = os.WriteFile(path.Join(migrationFilePath, baselineFileName), []byte(content.String()), 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot save %s, error: %v", baselineFileName, err)
+	}
+
+	return issues, target.saveRollback(migrationFilePath, saved)
+}
+
+// translate returns the objects of the schema translated into the target dialect, in creation order
+func (b *baselilner) translate(targetDialect string) ([]Object, []TranslationIssue, error) {
+	err := b.loadInstructions()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target, err := newTarget(targetDialect)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if b.baselineInstruction.translation.types == nil || target.baselineInstruction.translation.types == nil {
+		return nil, nil, fmt.Errorf("translation from %s to %s is not supported", b.dialect, targetDialect)
+	}
+
+	objects, err := b.objects()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	schema, err := b.inspectObjects(objects)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	t := &translator{
+		source:      b,
+		target:      target,
+		sourceRules: b.baselineInstruction.translation,
+		rules:       target.baselineInstruction.translation,
+		dialect:     targetDialect,
+		sequences:   make(map[string]bool),
+		indexNames:  make(map[string]string),
+		created:     make(map[string]bool),
+	}
+
+	for _, table := range schema.Tables {
+		t.table(table)
+	}
+	t.objects = append(t.objects, t.deferredForeignKeys...)
+
+	for _, table := range schema.Tables {
+		t.indexes(table)
+	}
+
+	for _, view := range schema.Views {
+		t.view(view)
+	}
+
+	for _, routine := range schema.Routines {
+		t.issue(routine.Type, routine.Name, "routines cannot be translated, rewrite it for "+targetDialect)
+	}
+
+	for _, trigger := range schema.Triggers {
+		t.issue(queryTypeTriggers, trigger.Name, "triggers cannot be translated, rewrite it for "+targetDialect)
+	}
+
+	for _, object := range objects {
+		t.untranslated(object)
+	}
+
+	return t.objects, t.issues, nil
+}
+
+// newTarget returns a baseliner of the target dialect, without a database, which writes the translated baseline
+func newTarget(dialect string) (*baselilner, error) {
+	target := &baselilner{dialect: dialect}
+	err := target.loadInstructions()
+	if err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+func (t *translator) issue(objectType, name, message string) {
+	t.issues = append(t.issues, TranslationIssue{ObjectType: objectType, Name: name, Message: message})
+}
+
+func (t *translator) add(objectType, name, sql string) {
+	t.objects = append(t.objects, Object{Type: objectType, Name: name, SQL: sql})
+}
+
+// table adds the table, the sequences of its auto incremented columns before it and its comments after it
+func (t *translator) table(table Table) {
+	var primaryKey *Constraint
+	for i, constraint := range table.Constraints {
+		if constraint.Type == ConstraintPrimaryKey {
+			primaryKey = &table.Constraints[i]
+		}
+	}
+
+	var definitions []string
+	inlinePrimaryKey := false
+	for _, column := range table.Columns {
+		definition, inline := t.column(table, column, primaryKey)
+		inlinePrimaryKey = inlinePrimaryKey || inline
+		definitions = append(definitions, definition)
+	}
+
+	t.created[table.Name] = true
+	for _, constraint := range table.Constraints {
+		if constraint.Type == ConstraintPrimaryKey && inlinePrimaryKey {
+			continue
+		}
+
+		definition, ok := t.constraint(table, constraint)
+		if ok {
+			definitions = append(definitions, definition)
+		}
+	}
+
+	sql := "CREATE TABLE " + t.quote(table.Name) + " (\n    " + strings.Join(definitions, ",\n    ") + "\n)"
+	if table.Comment != "" && t.rules.inlineComments {
+		sql += " COMMENT=" + literal(table.Comment)
+	}
+	t.add(queryTypeTables, table.Name, sql)
+
+	if !t.rules.commentOn {
+		if !t.rules.inlineComments && hasComments(table) {
+			t.issue(queryTypeTables, table.Name, "comments are not supported by "+t.dialect)
+		}
+
+		return
+	}
+
+	if table.Comment != "" {
+		t.add(queryTypeComments, table.Name, "COMMENT ON TABLE "+t.quote(table.Name)+" IS "+literal(table.Comment))
+	}
+
+	for _, column := range table.Columns {
+		if column.Comment != "" {
+			t.add(queryTypeComments, table.Name+"."+column.Name,
+				"COMMENT ON COLUMN "+t.quote(table.Name)+"."+t.quote(column.Name)+" IS "+literal(column.Comment))
+		}
+	}
+}
+
+// column returns the definition of the column, and if it is the inline primary key of the table
+func (t *translator) column(table Table, column Column, primaryKey *Constraint) (string, bool) {
+	columnType, canonicalType, autoIncrement := t.columnType(table, column, primaryKey)
+	definition := t.quote(column.Name) + " " + columnType
+
+	inlinePrimaryKey := false
+	if autoIncrement {
+		switch {
+		case t.rules.inlinePrimaryKey && primaryKey != nil && len(primaryKey.Columns) == 1:
+			definition += " PRIMARY KEY " + t.rules.autoIncrement
+			inlinePrimaryKey = true
+		case t.rules.inlinePrimaryKey:
+			t.issue(queryTypeTables, table.Name, fmt.Sprintf("column %s is auto incremented, %s only auto increments a single column primary key", column.Name, t.dialect))
+		case t.rules.sequenceDefault:
+			sequence := table.Name + "_" + column.Name + "_seq"
+			t.add(queryTypeSequences, sequence, "CREATE SEQUENCE "+t.quote(sequence))
+			definition += " DEFAULT nextval(" + literal(sequence) + ")"
+		default:
+			definition += " " + t.rules.autoIncrement
+		}
+	}
+
+	if column.NotNull && !inlinePrimaryKey {
+		definition += " NOT NULL"
+	}
+
+	if !autoIncrement {
+		if value, ok := t.defaultValue(table, column, canonicalType); ok && contains(t.rules.expressionDefaultTypes, canonicalType) {
+			definition += " DEFAULT (" + value + ")"
+		} else if ok {
+			definition += " DEFAULT " + value
+		}
+	}
+
+	if column.Comment != "" && t.rules.inlineComments {
+		definition += " COMMENT " + literal(column.Comment)
+	}
+
+	return definition, inlinePrimaryKey
+}
+
+// columnType returns the type of the column in the target dialect, its canonical type and if the column is auto incremented
+func (t *translator) columnType(table Table, column Column, primaryKey *Constraint) (string, string, bool) {
+	parsed, ok := t.parseType(column.Type)
+	if !ok {
+		t.issue(queryTypeTables, table.Name, fmt.Sprintf("type %s of column %s is unknown, it is copied unchanged", column.Type, column.Name))
+		return column.Type, "", false
+	}
+
+	if column.Type == "" {
+		t.issue(queryTypeTables, table.Name, fmt.Sprintf("column %s has no type, it is translated to %s", column.Name, t.rules.types[parsed.name]))
+	}
+
+	extra := strings.ToLower(column.Extra)
+	if strings.Contains(extra, "on update") {
+		t.issue(queryTypeTables, table.Name, fmt.Sprintf("column %s is updated on update, it is not translated", column.Name))
+	}
+
+	if strings.Contains(strings.ReplaceAll(extra, "default_generated", ""), "generated") {
+		t.issue(queryTypeTables, table.Name, fmt.Sprintf("column %s is generated, it is translated as a plain column", column.Name))
+	}
+
+	autoIncrement := strings.Contains(extra, "auto_increment") || extra == "identity" ||
+		strings.HasPrefix(strings.ToLower(column.Default.String), "nextval(")
+	if t.sourceRules.rowidPrimaryKey && strings.EqualFold(column.Type, "INTEGER") && primaryKey != nil && len(primaryKey.Columns) == 1 && primaryKey.Columns[0] == column.Name {
+		autoIncrement = true
+	}
+
+	if autoIncrement && parsed.name != typeInteger && parsed.name != typeBigInt && parsed.name != typeSmallInt {
+		t.issue(queryTypeTables, table.Name, fmt.Sprintf("column %s of type %s is auto incremented, only integer columns are", column.Name, column.Type))
+		autoIncrement = false
+	}
+
+	if autoIncrement {
+		if sequence := sequenceOfDefault(column.Default.String); sequence != "" {
+			_, name := splitTableName(sequence)
+			t.sequences[name] = true
+		}
+
+		// SQLite only auto increments INTEGER PRIMARY KEY
+		if t.rules.inlinePrimaryKey {
+			parsed = columnType{name: typeInteger}
+		}
+	}
+
+	typeName := t.rules.types[parsed.name]
+	switch {
+	case parsed.size != "" && (parsed.name == typeDecimal || parsed.name == typeVarchar || parsed.name == typeChar):
+		typeName += "(" + parsed.size + ")"
+	case parsed.name == typeVarchar && t.rules.varcharLength != "":
+		typeName += "(" + t.rules.varcharLength + ")"
+		t.issue(queryTypeTables, table.Name, fmt.Sprintf("column %s has no length, %s needs one, %s is used", column.Name, t.dialect, t.rules.varcharLength))
+	case parsed.name == typeTimestampTZ && t.rules.types[typeTimestampTZ] == t.rules.types[typeTimestamp]:
+		t.issue(queryTypeTables, table.Name, fmt.Sprintf("column %s has a time zone, %s has no type keeping it", column.Name, t.dialect))
+	}
+
+	return typeName, parsed.name, autoIncrement
+}
+
+// parseType returns the canonical type of a source type
+func (t *translator) parseType(sourceType string) (columnType, bool) {
+	sourceType = strings.ToLower(strings.TrimSpace(sourceType))
+	matches := columnTypeRegex.FindStringSubmatch(sourceType)
+	if matches != nil {
+		name := strings.Join(strings.Fields(matches[1]+" "+matches[3]), " ")
+		// MySQL booleans are tinyint(1), unsigned and zerofill do not change the type
+		if strings.HasPrefix(name, "tinyint") && matches[2] == "1" {
+			return columnType{name: typeBoolean}, true
+		}
+
+		name = strings.TrimSuffix(strings.TrimSuffix(name, " zerofill"), " unsigned")
+		if canonical, ok := typeAliases[name]; ok {
+			return columnType{name: canonical, size: strings.ReplaceAll(matches[2], " ", "")}, true
+		}
+	}
+
+	if !t.sourceRules.typeAffinity {
+		return columnType{}, false
+	}
+
+	// The affinity rules of SQLite, a column without a type is translated to text
+	switch {
+	case strings.Contains(sourceType, "int"):
+		return columnType{name: typeInteger}, true
+	case strings.Contains(sourceType, "char"), strings.Contains(sourceType, "clob"), strings.Contains(sourceType, "text"), sourceType == "":
+		return columnType{name: typeText}, true
+	case strings.Contains(sourceType, "blob"):
+		return columnType{name: typeBlob}, true
+	case strings.Contains(sourceType, "real"), strings.Contains(sourceType, "floa"), strings.Contains(sourceType, "doub"):
+		return columnType{name: typeDouble}, true
+	}
+
+	return columnType{name: typeDecimal}, true
+}
+
+// defaultValue translates the default of the column, literals and the current date and time are translated
+func (t *translator) defaultValue(table Table, column Column, canonicalType string) (string, bool) {
+	if !column.Default.Valid {
+		return "", false
+	}
+
+	value := strings.TrimSpace(column.Default.String)
+	for {
+		if matches := castRegex.FindStringSubmatch(value); matches != nil {
+			value = strings.TrimSpace(matches[1])
+			continue
+		}
+
+		if matches := castFunctionRegex.FindStringSubmatch(value); matches != nil {
+			value = strings.TrimSpace(matches[1])
+			continue
+		}
+
+		if unwrapped, ok := unwrapParentheses(value); ok {
+			value = unwrapped
+			continue
+		}
+
+		break
+	}
+
+	if canonicalType == typeBoolean {
+		switch strings.ToLower(strings.Trim(value, "'")) {
+		case "0", "f", "false", "b'0'":
+			return t.rules.booleans[0], true
+		case "1", "t", "true", "b'1'":
+			return t.rules.booleans[1], true
+		}
+	}
+
+	switch strings.ToLower(value) {
+	case "null":
+		return "", false
+	case "current_timestamp", "current_timestamp()", "now()", "localtimestamp", "transaction_timestamp()", "get_current_timestamp()", "datetime('now')":
+		return "CURRENT_TIMESTAMP", true
+	case "current_date", "current_date()", "curdate()", "date('now')":
+		return t.rules.currentDate, true
+	}
+
+	if t.sourceRules.unquotedDefaults && !strings.Contains(strings.ToLower(column.Extra), "default_generated") && !numberRegex.MatchString(value) {
+		value = literal(value)
+	}
+
+	if numberRegex.MatchString(value) || stringRegex.MatchString(value) {
+		return value, true
+	}
+
+	t.issue(queryTypeTables, table.Name, fmt.Sprintf("default %s of column %s is copied unchanged", value, column.Name))
+	return t.requote(value), true
+}
+
+// constraint returns the definition of a constraint of the table
+// A foreign key to a table which is not created yet is added after the tables, when the target cannot refer forward
+func (t *translator) constraint(table Table, constraint Constraint) (string, bool) {
+	var definition string
+	if constraint.Name != "" && constraint.Type != ConstraintPrimaryKey {
+		definition = "CONSTRAINT " + t.quote(constraint.Name) + " "
+	}
+
+	switch constraint.Type {
+	case ConstraintPrimaryKey, ConstraintUnique:
+		definition += constraint.Type + " (" + t.quoteList(constraint.Columns) + ")"
+	case ConstraintForeignKey:
+		definition += "FOREIGN KEY (" + t.quoteList(constraint.Columns) + ") REFERENCES " + t.quote(constraint.ReferencedTable) +
+			" (" + t.quoteList(constraint.ReferencedColumns) + ")"
+		if t.created[constraint.ReferencedTable] || t.rules.forwardForeignKeys {
+			break
+		}
+
+		if !t.rules.alterForeignKeys {
+			t.issue(queryTypeTables, table.Name, fmt.Sprintf("foreign key to %s is left out, the tables refer to each other and %s cannot add it later", constraint.ReferencedTable, t.dialect))
+			return "", false
+		}
+
+		name := table.Name + "." + constraint.Name
+		t.deferredForeignKeys = append(t.deferredForeignKeys, Object{Type: queryTypeConstraints, Name: name, SQL: "ALTER TABLE " + t.quote(table.Name) + " ADD " + definition})
+		return "", false
+	case ConstraintCheck:
+		check := strings.TrimSpace(constraint.Check)
+		if !strings.HasPrefix(strings.ToUpper(check), "CHECK") {
+			check = "CHECK (" + check + ")"
+		}
+
+		definition += t.requote(check)
+		t.issue(queryTypeTables, table.Name, fmt.Sprintf("check %s is copied unchanged", check))
+	default:
+		t.issue(queryTypeTables, table.Name, fmt.Sprintf("%s constraint %s cannot be translated", constraint.Type, constraint.Name))
+		return "", false
+	}
+
+	return definition, true
+}
+
+// indexes adds the indexes of the table, expressions are copied with the identifiers quoted for the target
+func (t *translator) indexes(table Table) {
+	for _, index := range table.Indexes {
+		if other, ok := t.indexNames[index.Name]; ok && other != table.Name {
+			t.issue(queryTypeIndex, index.Name, fmt.Sprintf("index name is used on %s and %s, rename one of them if %s needs unique index names", other, table.Name, t.dialect))
+		}
+		t.indexNames[index.Name] = table.Name
+
+		columns := make([]string, len(index.Columns))
+		for i, column := range index.Columns {
+			if !identifierRegex.MatchString(column) {
+				t.issue(queryTypeIndex, index.Name, fmt.Sprintf("expression %s is copied unchanged", column))
+				columns[i] = t.requote(column)
+				continue
+			}
+
+			name := strings.Trim(column, "`\"[]")
+			columns[i] = t.quote(name)
+			if parsed, ok := t.parseType(columnTypeOf(table, name)); ok && contains(t.rules.indexPrefixTypes, parsed.name) {
+				t.issue(queryTypeIndex, index.Name, fmt.Sprintf("column %s needs a prefix length to be indexed by %s", name, t.dialect))
+			}
+		}
+
+		sql := "CREATE INDEX "
+		if index.Unique {
+			sql = "CREATE UNIQUE INDEX "
+		}
+		t.add(queryTypeIndex, index.Name, sql+t.quote(index.Name)+" ON "+t.quote(table.Name)+" ("+strings.Join(columns, ", ")+")")
+	}
+}
+
+// view copies the SQL of the view with the identifiers quoted for the target
+func (t *translator) view(view View) {
+	objectType := queryTypeViews
+	sql := t.requote(strings.TrimSpace(view.SQL))
+	if view.Materialized && t.rules.materializedViews {
+		objectType = queryTypeMaterialViews
+	} else if view.Materialized {
+		t.issue(queryTypeMaterialViews, view.Name, t.dialect+" has no materialized views, it is created as a view")
+		sql = materializedRegex.ReplaceAllString(sql, "VIEW")
+	}
+
+	t.add(objectType, view.Name, strings.TrimSuffix(sql, ";"))
+	t.issue(objectType, view.Name, "the query is copied unchanged, review its functions and syntax")
+}
+
+// untranslated reports the objects of the source which are not part of the model and are not translated
+func (t *translator) untranslated(object Object) {
+	switch object.Type {
+	case queryTypeTables, queryTypeIndex, queryTypeViews, queryTypeMaterialViews, queryTypeFunctions, queryTypeProcedures,
+		queryTypeTriggers, queryTypeConstraints, queryTypeComments:
+		return
+	case queryTypeSequences:
+		if _, name := splitTableName(object.Name); t.sequences[name] {
+			return
+		}
+	}
+
+	t.issue(object.Type, object.Name, object.Type+" is not translated, create it for "+t.dialect)
+}
+
+// quote quotes the name for the target, every part of a schema qualified name is quoted
+func (t *translator) quote(name string) string {
+	return t.target.quoteName(name)
+}
+
+func (t *translator) quoteList(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = t.quote(name)
+	}
+
+	return strings.Join(quoted, ", ")
+}
+
+// requote replaces the identifier quotes of the source with the quotes of the target, outside of string literals
+func (t *translator) requote(sql string) string {
+	from := t.source.baselineInstruction.identifierQuote
+	to := t.target.baselineInstruction.identifierQuote
+	if from == to || from == "" || to == "" {
+		return sql
+	}
+
+	var result strings.Builder
+	inString := false
+	for _, r := range sql {
+		switch {
+		case r == '\'':
+			inString = !inString
+		case !inString && string(r) == from:
+			result.WriteString(to)
+			continue
+		}
+
+		result.WriteRune(r)
+	}
+
+	return result.String()
+}
+
+// sequenceOfDefault returns the sequence of a default like nextval('users_id_seq'::regclass)
+func sequenceOfDefault(value string) string {
+	start := strings.Index(value, "'")
+	end := strings.LastIndex(value, "'")
+	if start < 0 || end <= start {
+		return ""
+	}
+
+	return strings.Trim(value[start+1:end], "\"")
+}
+
+func columnTypeOf(table Table, name string) string {
+	for _, column := range table.Columns {
+		if column.Name == name {
+			return column.Type
+		}
+	}
+
+	return ""
+}
+
+func hasComments(table Table) bool {
+	if table.Comment != "" {
+		return true
+	}
+
+	for _, column := range table.Columns {
+		if column.Comment != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unwrapParentheses removes the parentheses enclosing the whole value, like SQLite returns expression defaults
+func unwrapParentheses(value string) (string, bool) {
+	if !strings.HasPrefix(value, "(") || !strings.HasSuffix(value, ")") {
+		return value, false
+	}
+
+	depth := 0
+	inString := false
+	for i, r := range value {
+		switch {
+		case r == '\'':
+			inString = !inString
+		case inString:
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth == 0 && i < len(value)-1 {
+				return value, false
+			}
+		}
+	}
+
+	return strings.TrimSpace(value[1 : len(value)-1]), true
+}
+
+// literal quotes the value as a string literal
+func literal(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
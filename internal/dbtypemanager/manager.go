@@ -14,6 +14,7 @@ const (
 	DbTypePostgres = "pg"
 	DbTypeMySQL    = "mysql"
 	DbTypeFirebird = "firebird"
+	DbTypeDuckDB   = "duckdb"
 )
 
 // GetDiverType returns driver type according to the passed DB
@@ -36,5 +37,14 @@ func GetDiverType(db *sql.DB) (string, error) {
 		return DbTypeFirebird, nil
 	}
 
+	if strings.Contains(driverType, "duckdb") {
+		return DbTypeDuckDB, nil
+	}
+
 	return "", fmt.Errorf("DB manager: the driver used %s does not match any known driver by the application", driverType)
 }
+
+// QuotePostgresIdentifier quotes a schema or table name for PostgreSQL, doubling embedded quotes
+func QuotePostgresIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
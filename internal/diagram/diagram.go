@@ -0,0 +1,122 @@
+// Package diagram writes entity relationship diagrams of a schema
+package diagram
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/olbrichattila/godbmigrator/internal/baseliner"
+)
+
+// Diagram formats
+const (
+	FormatMermaid = "mermaid"
+	FormatDOT     = "dot"
+)
+
+// IncludeFunc tells if a table is drawn, foreign keys are drawn when both of their tables are
+type IncludeFunc func(table string) bool
+
+// relationship is a foreign key between two drawn tables
+type relationship struct {
+	table      baseliner.Table
+	constraint baseliner.Constraint
+	// optional is true when a foreign key column is nullable, so a row may have no parent
+	optional bool
+}
+
+// Write writes the diagram of the tables, their columns and foreign keys in the given format
+func Write(w io.Writer, format string, schema *baseliner.Schema, include IncludeFunc) error {
+	var tables []baseliner.Table
+	drawn := make(map[string]bool)
+	for _, table := range schema.Tables {
+		if include == nil || include(table.Name) {
+			tables = append(tables, table)
+			drawn[table.Name] = true
+		}
+	}
+
+	var relationships []relationship
+	for _, table := range tables {
+		for _, constraint := range table.Constraints {
+			if constraint.Type != baseliner.ConstraintForeignKey || !drawn[constraint.ReferencedTable] {
+				continue
+			}
+
+			relationships = append(relationships, relationship{
+				table:      table,
+				constraint: constraint,
+				optional:   hasNullable(table, constraint.Columns),
+			})
+		}
+	}
+
+	var diagram string
+	switch format {
+	case FormatMermaid:
+		diagram = mermaid(tables, relationships)
+	case FormatDOT:
+		diagram = dot(tables, relationships)
+	default:
+		return fmt.Errorf("unknown diagram format %s, use %s or %s", format, FormatMermaid, FormatDOT)
+	}
+
+	_, err := io.WriteString(w, diagram)
+	if err != nil {
+		return fmt.Errorf("cannot write diagram, error: %v", err)
+	}
+
+	return nil
+}
+
+// keys returns the key markers of a column, PK, FK and UK
+func keys(table baseliner.Table, column baseliner.Column) []string {
+	var markers []string
+	if column.PrimaryKey {
+		markers = append(markers, "PK")
+	}
+
+	for _, marker := range []struct {
+		constraintType string
+		marker         string
+	}{{baseliner.ConstraintForeignKey, "FK"}, {baseliner.ConstraintUnique, "UK"}} {
+		for _, constraint := range table.Constraints {
+			if constraint.Type == marker.constraintType && contains(constraint.Columns, column.Name) {
+				markers = append(markers, marker.marker)
+				break
+			}
+		}
+	}
+
+	return markers
+}
+
+// label names the relationship by the constraint, or by its columns when the constraint has no name
+func label(constraint baseliner.Constraint) string {
+	if constraint.Name != "" {
+		return constraint.Name
+	}
+
+	return strings.Join(constraint.Columns, ", ")
+}
+
+func hasNullable(table baseliner.Table, columns []string) bool {
+	for _, column := range table.Columns {
+		if contains(columns, column.Name) && !column.NotNull && !column.PrimaryKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,54 @@
+package diagram
+
+import (
+	"html"
+	"strings"
+
+	"github.com/olbrichattila/godbmigrator/internal/baseliner"
+)
+
+// dot writes a Graphviz digraph, tables are HTML table nodes with a port per column, foreign keys point to the referenced columns
+func dot(tables []baseliner.Table, relationships []relationship) string {
+	var diagram strings.Builder
+	diagram.WriteString("digraph schema {\n")
+	diagram.WriteString("    rankdir=LR;\n")
+	diagram.WriteString("    node [shape=plaintext];\n")
+	for _, table := range tables {
+		diagram.WriteString("    " + dotID(table.Name) + " [label=<<TABLE BORDER=\"0\" CELLBORDER=\"1\" CELLSPACING=\"0\">")
+		diagram.WriteString("<TR><TD BGCOLOR=\"lightgrey\"><B>" + html.EscapeString(table.Name) + "</B></TD></TR>")
+		for _, column := range table.Columns {
+			text := column.Name + " " + column.Type
+			if markers := keys(table, column); len(markers) > 0 {
+				text += " " + strings.Join(markers, ", ")
+			}
+			diagram.WriteString("<TR><TD PORT=" + dotID(column.Name) + " ALIGN=\"LEFT\">" + html.EscapeString(text) + "</TD></TR>")
+		}
+		diagram.WriteString("</TABLE>>];\n")
+	}
+
+	for _, relationship := range relationships {
+		constraint := relationship.constraint
+		from, to := dotID(relationship.table.Name), dotID(constraint.ReferencedTable)
+		if len(constraint.Columns) > 0 {
+			from += ":" + dotID(constraint.Columns[0])
+		}
+		if len(constraint.ReferencedColumns) > 0 {
+			to += ":" + dotID(constraint.ReferencedColumns[0])
+		}
+
+		style := ""
+		if relationship.optional {
+			style = ", style=dashed"
+		}
+		diagram.WriteString("    " + from + " -> " + to + " [label=" + dotID(label(constraint)) + style + "];\n")
+	}
+
+	diagram.WriteString("}\n")
+
+	return diagram.String()
+}
+
+// dotID quotes an identifier, escaping quotes and backslashes
+func dotID(id string) string {
+	return "\"" + strings.NewReplacer("\\", "\\\\", "\"", "\\\"").Replace(id) + "\""
+}
@@ -0,0 +1,53 @@
+package diagram
+
+import (
+	"strings"
+
+	"github.com/olbrichattila/godbmigrator/internal/baseliner"
+)
+
+// mermaid writes an erDiagram, a child table has zero or more rows for a parent row
+func mermaid(tables []baseliner.Table, relationships []relationship) string {
+	var diagram strings.Builder
+	diagram.WriteString("erDiagram\n")
+	for _, table := range tables {
+		diagram.WriteString("    " + mermaidName(table.Name) + " {\n")
+		for _, column := range table.Columns {
+			// Mermaid needs a type, SQLite columns may have none
+			columnType := column.Type
+			if columnType == "" {
+				columnType = "ANY"
+			}
+
+			diagram.WriteString("        " + mermaidName(columnType) + " " + mermaidName(column.Name))
+			if markers := keys(table, column); len(markers) > 0 {
+				diagram.WriteString(" " + strings.Join(markers, ", "))
+			}
+			diagram.WriteString("\n")
+		}
+		diagram.WriteString("    }\n")
+	}
+
+	for _, relationship := range relationships {
+		parent := "||"
+		if relationship.optional {
+			parent = "o|"
+		}
+
+		diagram.WriteString("    " + mermaidName(relationship.table.Name) + " }o--" + parent + " " + mermaidName(relationship.constraint.ReferencedTable) +
+			" : \"" + strings.ReplaceAll(label(relationship.constraint), "\"", "'") + "\"\n")
+	}
+
+	return diagram.String()
+}
+
+// mermaidName replaces the characters Mermaid does not accept in names and types, like spaces, dots and commas
+func mermaidName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' || r == '-' || r == '(' || r == ')' || r == '[' || r == ']' {
+			return r
+		}
+
+		return '_'
+	}, name)
+}
@@ -1,32 +0,0 @@
-// Package helper contains variable helper functions can be used across packages
-package helper
-
-import (
-	"crypto/md5"
-	"encoding/hex"
-	"fmt"
-	"io"
-	"os"
-)
-
-// FileExists check if the file exists
-func FileExists(filename string) bool {
-	_, err := os.Stat(filename)
-	return !os.IsNotExist(err)
-}
-
-// CalculateFileMD5 returns the MD5 hash of a string
-func CalculateFileMD5(value string) (string, error) {
-	file, err := os.Open(value)
-	if err != nil {
-		return "", fmt.Errorf("failed to open file: %w", err)
-	}
-	defer file.Close()
-
-	hash := md5.New()
-	if _, err := io.Copy(hash, file); err != nil {
-		return "", fmt.Errorf("failed to calculate hash: %w", err)
-	}
-
-	return hex.EncodeToString(hash.Sum(nil)), nil
-}
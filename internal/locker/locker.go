@@ -0,0 +1,148 @@
+// Package locker serializes migration runs across processes using database advisory locks
+package locker
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"time"
+
+	"github.com/olbrichattila/godbmigrator/internal/dbtypemanager"
+)
+
+const pollInterval = 100 * time.Millisecond
+
+// ErrTimeout is returned when the lock could not be acquired within the timeout
+var ErrTimeout = errors.New("could not acquire migration lock within the timeout")
+
+// Locker acquires and releases the migration lock
+type Locker interface {
+	Lock(timeout time.Duration) error
+	Unlock() error
+}
+
+// New returns a locker for the dialect, returns an error if the dialect does not support advisory locks
+func New(db *sql.DB, dialect, name string) (Locker, error) {
+	switch dialect {
+	case dbtypemanager.DbTypePostgres:
+		return &pgLocker{db: db, key: lockKey(name)}, nil
+	case dbtypemanager.DbTypeMySQL:
+		return &mySQLLocker{db: db, name: name}, nil
+	default:
+		return nil, fmt.Errorf("migration lock is not supported for %s database type", dialect)
+	}
+}
+
+// Supported tells if the dialect can be used with a lock timeout
+func Supported(dialect string) bool {
+	return dialect == dbtypemanager.DbTypePostgres || dialect == dbtypemanager.DbTypeMySQL
+}
+
+// pgLocker holds a session level advisory lock, so the connection is pinned until unlock
+type pgLocker struct {
+	db   *sql.DB
+	key  int64
+	conn *sql.Conn
+}
+
+func (l *pgLocker) Lock(timeout time.Duration) error {
+	ctx := context.Background()
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		var acquired bool
+		err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&acquired)
+		if err != nil {
+			_ = conn.Close()
+			return fmt.Errorf("cannot acquire migration lock, error: %w", err)
+		}
+
+		if acquired {
+			l.conn = conn
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			_ = conn.Close()
+			return ErrTimeout
+		}
+
+		time.Sleep(pollInterval)
+	}
+}
+
+func (l *pgLocker) Unlock() error {
+	if l.conn == nil {
+		return nil
+	}
+
+	defer func() {
+		_ = l.conn.Close()
+		l.conn = nil
+	}()
+
+	_, err := l.conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", l.key)
+
+	return err
+}
+
+// mySQLLocker uses GET_LOCK, which waits for the timeout itself but is bound to the connection as well
+type mySQLLocker struct {
+	db   *sql.DB
+	name string
+	conn *sql.Conn
+}
+
+func (l *mySQLLocker) Lock(timeout time.Duration) error {
+	ctx := context.Background()
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+
+	var acquired sql.NullInt64
+	seconds := int64(math.Ceil(timeout.Seconds()))
+	err = conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", l.name, seconds).Scan(&acquired)
+	if err != nil {
+		_ = conn.Close()
+		return fmt.Errorf("cannot acquire migration lock, error: %w", err)
+	}
+
+	if !acquired.Valid || acquired.Int64 != 1 {
+		_ = conn.Close()
+		return ErrTimeout
+	}
+
+	l.conn = conn
+
+	return nil
+}
+
+func (l *mySQLLocker) Unlock() error {
+	if l.conn == nil {
+		return nil
+	}
+
+	defer func() {
+		_ = l.conn.Close()
+		l.conn = nil
+	}()
+
+	_, err := l.conn.ExecContext(context.Background(), "SELECT RELEASE_LOCK(?)", l.name)
+
+	return err
+}
+
+func lockKey(name string) int64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(name))
+
+	return int64(hash.Sum64())
+}
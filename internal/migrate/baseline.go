@@ -0,0 +1,239 @@
+package migrate
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/olbrichattila/godbmigrator/internal/migrationfile"
+)
+
+// BaselineMarkerPrefix starts the migration row recording the last migration a baseline covers, like baseline@<file name>
+const BaselineMarkerPrefix = "baseline@"
+
+// SquashableFiles returns the migration files up to and including upTo, which can be replaced with a baseline
+// The database has to be exactly at upTo, otherwise the baseline would not match the squashed files
+func (m *migration) SquashableFiles(
+	migrationProvider MigrationProvider,
+	upTo string,
+) ([]migrationfile.File, error) {
+	m.migrationProvider = migrationProvider
+
+	files, err := m.migrationFileManager.OrderedMigrationFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	found := false
+	var squashable []migrationfile.File
+	for _, file := range files {
+		if file.Repeatable || !coveredBy(file.Name, upTo) {
+			continue
+		}
+
+		if file.Source != "" {
+			return nil, fmt.Errorf("cannot squash migration %s of source %s, only the default source can be squashed", file.Name, file.Source)
+		}
+
+		exists, err := m.migrationProvider.MigrationExistsForFile(file.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			return nil, fmt.Errorf("cannot squash, migration %s is not applied", file.Name)
+		}
+
+		found = found || file.Name == upTo
+		squashable = append(squashable, file)
+	}
+
+	if !found {
+		return nil, fmt.Errorf("cannot squash, migration file %s does not exist", upTo)
+	}
+
+	migrations, err := m.migrationProvider.Migrations(false)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, mig := range migrations {
+		if !coveredBy(mig.Migration, upTo) {
+			return nil, fmt.Errorf("cannot squash, the database is past %s, migration %s is applied", upTo, mig.Migration)
+		}
+	}
+
+	return squashable, nil
+}
+
+// SyncBaseline records the baseline version in a database which applied the squashed migrations one by one
+// Rows of the squashed migrations are soft deleted, as their files are archived, the baseline marker replaces them
+// Nothing is done while the file of version exists, as the migrations are not squashed and can be replayed
+func (m *migration) SyncBaseline(migrationProvider MigrationProvider, version string) error {
+	m.migrationProvider = migrationProvider
+
+	baselineVersion, err := m.migrationProvider.BaselineVersion()
+	if err != nil {
+		return err
+	}
+
+	if baselineVersion != "" && coveredBy(version, baselineVersion) {
+		return nil
+	}
+
+	if _, err := m.migrationFileManager.ResolveFile("", version); err == nil {
+		return nil
+	}
+
+	migrations, err := m.migrationProvider.Migrations(false)
+	if err != nil {
+		return err
+	}
+
+	if len(migrations) == 0 && baselineVersion == "" {
+		return fmt.Errorf("the baseline of %s is not loaded, run LoadBaseline first", version)
+	}
+
+	exists, err := m.migrationProvider.MigrationExistsForFile(version)
+	if err != nil {
+		return err
+	}
+
+	if !exists {
+		return fmt.Errorf("the database is behind the baseline of %s, apply the squashed migrations first", version)
+	}
+
+	for _, mig := range migrations {
+		if !coveredBy(mig.Migration, version) {
+			continue
+		}
+
+		err = m.migrationProvider.RemoveFromMigration(mig.Migration)
+		if err != nil {
+			return err
+		}
+	}
+
+	return m.migrationProvider.AddBaselineMarker(version)
+}
+
+// AppliedVersion returns the newest applied versioned migration, or the baseline version if nothing was applied after it
+func (m *migration) AppliedVersion(migrationProvider MigrationProvider) (string, error) {
+	m.migrationProvider = migrationProvider
+
+	version, err := m.migrationProvider.BaselineVersion()
+	if err != nil {
+		return "", err
+	}
+
+	migrations, err := m.migrationProvider.Migrations(false)
+	if err != nil {
+		return "", err
+	}
+
+	for _, mig := range migrations {
+		if version == "" || !coveredBy(mig.Migration, version) {
+			version = mig.Migration
+		}
+	}
+
+	return version, nil
+}
+
+// Baseline marks the migrations up to and including version as applied, without running them
+// It adopts the migrator on an existing database, which already has the schema of these migrations
+func (m *migration) Baseline(migrationProvider MigrationProvider, version string) error {
+	m.migrationProvider = migrationProvider
+
+	files, err := m.migrationFileManager.OrderedMigrationFiles()
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, file := range files {
+		if !file.Repeatable && file.Name == version {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return fmt.Errorf("cannot baseline, migration file %s does not exist", version)
+	}
+
+	baselineVersion, err := m.migrationProvider.BaselineVersion()
+	if err != nil {
+		return err
+	}
+
+	if baselineVersion != "" && coveredBy(version, baselineVersion) {
+		return fmt.Errorf("cannot baseline at %s, the database is already baselined at %s", version, baselineVersion)
+	}
+
+	return m.migrationProvider.AddBaselineMarker(version)
+}
+
+// RevertBaseline forgets the baseline version and the migrations it covers, after dropObjects dropped its schema
+// It is refused while a migration applied after the baseline is not rolled back
+func (m *migration) RevertBaseline(migrationProvider MigrationProvider, dropObjects func() error) error {
+	m.migrationProvider = migrationProvider
+
+	version, err := m.migrationProvider.BaselineVersion()
+	if err != nil {
+		return err
+	}
+
+	migrations, err := m.migrationProvider.Migrations(false)
+	if err != nil {
+		return err
+	}
+
+	for _, mig := range migrations {
+		if version == "" || !coveredBy(mig.Migration, version) {
+			return fmt.Errorf("cannot roll back the baseline, migration %s is applied after it, roll it back first", mig.Migration)
+		}
+	}
+
+	err = dropObjects()
+	if err != nil {
+		return err
+	}
+
+	for _, mig := range migrations {
+		err = m.migrationProvider.RemoveFromMigration(mig.Migration)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Baseline and Squash may both have recorded a marker, every one of them is covered
+	for version != "" {
+		err = m.migrationProvider.RemoveFromMigration(BaselineMarkerPrefix + version)
+		if err != nil {
+			return err
+		}
+
+		version, err = m.migrationProvider.BaselineVersion()
+		if err != nil {
+			return err
+		}
+	}
+
+	return m.forgetRepeatableMigrations()
+}
+
+// isVersioned tells if a migration row belongs to a versioned migration, not to a repeatable one or a baseline marker
+func isVersioned(fileName string) bool {
+	return !migrationfile.IsRepeatable(fileName) && !strings.HasPrefix(fileName, BaselineMarkerPrefix)
+}
+
+// isCoveredByBaseline tells if the schema of a versioned migration file is part of the loaded baseline
+func isCoveredByBaseline(file migrationfile.File, baselineVersion string) bool {
+	return baselineVersion != "" && !file.Repeatable && coveredBy(file.Name, baselineVersion)
+}
+
+// coveredBy tells if the migration is ordered before or at version, the same order as the migration timeline
+func coveredBy(fileName, version string) bool {
+	return path.Base(fileName) <= path.Base(version)
+}
@@ -6,37 +6,60 @@ import (
 	"crypto/md5"
 	"database/sql"
 	"encoding/hex"
+	"errors"
 	"fmt"
-	"os"
+	"io/fs"
 	"strconv"
 	"strings"
 
 	"github.com/olbrichattila/godbmigrator/config"
-	"github.com/olbrichattila/godbmigrator/internal/helper"
+	"github.com/olbrichattila/godbmigrator/internal/dbtypemanager"
 	"github.com/olbrichattila/godbmigrator/internal/messager"
 	"github.com/olbrichattila/godbmigrator/internal/migrationfile"
 )
 
 // Migrator abstracts migration logic
 type Migrator interface {
-	Migrate(migrationProvider MigrationProvider, migrationFilePath string, count int) error
-	Rollback(migrationProvider MigrationProvider, migrationFilePath string, count int, isCompleteRollback bool) error
-	Report(migrationProvider MigrationProvider, migrationFilePath string) (string, error)
-	ChecksumValidation(migrationProvider MigrationProvider, migrationFilePath string) []string
+	Migrate(migrationProvider MigrationProvider, count int, scope migrationfile.Scope) error
+	Rollback(migrationProvider MigrationProvider, count int, isCompleteRollback bool, scope migrationfile.Scope) error
+	Report(migrationProvider MigrationProvider) (string, error)
+	Status(migrationProvider MigrationProvider, scope migrationfile.Scope) ([]FileStatus, error)
+	ChecksumValidation(migrationProvider MigrationProvider) []string
+	SquashableFiles(migrationProvider MigrationProvider, upTo string) ([]migrationfile.File, error)
+	SyncBaseline(migrationProvider MigrationProvider, version string) error
+	AppliedVersion(migrationProvider MigrationProvider) (string, error)
+	Baseline(migrationProvider MigrationProvider, version string) error
+	RevertBaseline(migrationProvider MigrationProvider, dropObjects func() error) error
+	Seed(seedProvider SeedProvider, seedFileManager migrationfile.Manager, fresh bool, scope migrationfile.Scope) error
+}
+
+// Migration file statuses
+const (
+	StatusApplied       = "applied"
+	StatusPending       = "pending"
+	StatusNotApplicable = "not applicable"
+)
+
+// FileStatus is the state of a migration file in the active scope
+type FileStatus struct {
+	Source   string
+	FileName string
+	Status   string
 }
 
 type migration struct {
 	db                   *sql.DB
+	searchPath           []string
 	migrationProvider    MigrationProvider
-	migrationFilePath    string
 	migrationFileManager migrationfile.Manager
 	msg                  messager.Messager
 }
 
-// New creates a new migration
-func New(db *sql.DB, migrationFileManager migrationfile.Manager, msg messager.Messager) Migrator {
+// New creates a new migration, a non empty searchPath is set as PostgreSQL search_path for every migration statement
+func New(db *sql.DB, migrationFileManager migrationfile.Manager, msg messager.Messager, searchPath []string) Migrator {
 	return &migration{
 		db:                   db,
+		searchPath:           searchPath,
 		migrationFileManager: migrationFileManager,
 		msg:                  msg,
 	}
@@ -44,26 +67,45 @@ func New(db *sql.DB, migrationFileManager migrationfile.Manager, msg messager.Me
 
 func (m *migration) Migrate(
 	migrationProvider MigrationProvider,
-	migrationFilePath string,
 	count int,
+	scope migrationfile.Scope,
 ) error {
-	m.migrationFilePath = migrationFilePath
 	m.migrationProvider = migrationProvider
 	m.migrationProvider.ResetDate()
 
-	fileNames, err := m.migrationFileManager.OrderedMigrationFiles()
+	files, err := m.migrationFileManager.OrderedMigrationFiles()
+	if err != nil {
+		return err
+	}
+
+	baselineVersion, err := m.migrationProvider.BaselineVersion()
 	if err != nil {
 		return err
 	}
 
 	migrateCount := 0
-	for _, fileName := range fileNames {
+	for _, file := range files {
 		if count > 0 {
 			if migrateCount == count {
 				break
 			}
 		}
-		migrated, err := m.executeSQLFile(fileName)
+
+		if isCoveredByBaseline(file, baselineVersion) {
+			continue
+		}
+
+		if !scope.Applicable(file) {
+			m.messageDispatch(config.SkipNotApplicable, file.Name)
+			continue
+		}
+
+		executeFile := m.executeSQLFile
+		if file.Repeatable {
+			executeFile = m.executeRepeatableSQLFile
+		}
+
+		migrated, err := executeFile(file)
 		if err != nil {
 			return err
 		}
@@ -80,13 +122,12 @@ func (m *migration) Migrate(
 
 func (m *migration) Rollback(
 	migrationProvider MigrationProvider,
-	migrationFilePath string,
 	count int,
 	isCompleteRollback bool,
+	scope migrationfile.Scope,
 ) error {
 	var err error
 
-	m.migrationFilePath = migrationFilePath
 	m.migrationProvider = migrationProvider
 	migrations, err := m.migrationProvider.Migrations(!isCompleteRollback)
 	if err != nil {
@@ -94,6 +135,10 @@ func (m *migration) Rollback(
 	}
 	if len(migrations) == 0 {
 		m.messageDispatch(config.NothingToRollback, "")
+		if isCompleteRollback {
+			return m.forgetRepeatableMigrations()
+		}
+
 		return nil
 	}
 
@@ -105,23 +150,100 @@ func (m *migration) Rollback(
 			}
 		}
 
-		err = m.executeRollbackSQLFile(mig.Migration)
+		if !m.isRollbackApplicable(mig, scope) {
+			m.messageDispatch(config.SkipNotApplicable, mig.Migration)
+			continue
+		}
+
+		err = m.executeRollbackSQLFile(mig.Source, mig.Migration)
 		if err != nil {
 			return err
 		}
 		rollbackCount++
 	}
 
+	if isCompleteRollback {
+		err = m.forgetRepeatableMigrations()
+		if err != nil {
+			return err
+		}
+	}
+
 	m.messageDispatch(config.RolledBack, strconv.Itoa(rollbackCount))
 
 	return nil
 }
 
+// isRollbackApplicable checks the scope of an applied migration, if its file is gone the rollback decides what to do
+func (m *migration) isRollbackApplicable(mig MigrationRow, scope migrationfile.Scope) bool {
+	file, err := m.migrationFileManager.ResolveFile(mig.Source, mig.Migration)
+	if err != nil {
+		return true
+	}
+
+	return scope.Applicable(file)
+}
+
+func (m *migration) Status(
+	migrationProvider MigrationProvider,
+	scope migrationfile.Scope,
+) ([]FileStatus, error) {
+	m.migrationProvider = migrationProvider
+
+	files, err := m.migrationFileManager.OrderedMigrationFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	baselineVersion, err := m.migrationProvider.BaselineVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]FileStatus, 0, len(files))
+	for _, file := range files {
+		exists, err := m.isApplied(file)
+		if err != nil {
+			return nil, err
+		}
+
+		exists = exists || isCoveredByBaseline(file, baselineVersion)
+
+		status := StatusPending
+		if exists {
+			status = StatusApplied
+		} else if !scope.Applicable(file) {
+			status = StatusNotApplicable
+		}
+
+		statuses = append(statuses, FileStatus{Source: file.Source, FileName: file.Name, Status: status})
+	}
+
+	return statuses, nil
+}
+
+// isApplied tells if a migration was run, a repeatable migration only counts as applied while its checksum matches
+func (m *migration) isApplied(file migrationfile.File) (bool, error) {
+	if !file.Repeatable {
+		return m.migrationProvider.MigrationExistsForFile(file.Name)
+	}
+
+	checksum, exists, err := m.migrationProvider.MigrationChecksum(file.Name)
+	if err != nil || !exists {
+		return false, err
+	}
+
+	content, err := m.migrationFileManager.ReadFile(file.Source, file.Name)
+	if err != nil {
+		return false, err
+	}
+
+	return checksum == m.getHash(string(content)), nil
+}
+
 func (m *migration) Report(
 	migrationProvider MigrationProvider,
-	migrationFilePath string,
 ) (string, error) {
-	m.migrationFilePath = migrationFilePath
 	m.migrationProvider = migrationProvider
 
 	return m.migrationProvider.Report()
@@ -129,39 +251,38 @@ func (m *migration) Report(
 
 func (m *migration) ChecksumValidation(
 	migrationProvider MigrationProvider,
-	migrationFilePath string,
 ) []string {
-	errors := make([]string, 0)
-	m.migrationFilePath = migrationFilePath
+	errorList := make([]string, 0)
 	m.migrationProvider = migrationProvider
 	migrations, err := m.migrationProvider.Migrations(false)
 	if err != nil {
-		errors = append(errors, err.Error())
-		return errors
+		errorList = append(errorList, err.Error())
+		return errorList
 	}
 
 	for _, mig := range migrations {
-		filePath := m.migrationFilePath + "/" + mig.Migration
-		if !helper.FileExists(filePath) {
-			errors = append(errors, fmt.Sprintf("migration file for checksum does not %s exists", mig.Migration))
+		content, err := m.migrationFileManager.ReadFile(mig.Source, mig.Migration)
+		if errors.Is(err, fs.ErrNotExist) {
+			errorList = append(errorList, fmt.Sprintf("migration file for checksum does not %s exists", mig.Migration))
 			continue
 		}
 
-		md5, err := helper.CalculateFileMD5(m.migrationFilePath + "/" + mig.Migration)
 		if err != nil {
-			errors = append(errors, fmt.Sprintf("migration file for checksum could not be opened %s exists", mig.Migration))
+			errorList = append(errorList, fmt.Sprintf("migration file for checksum could not be opened %s exists", mig.Migration))
 			continue
 		}
 
+		md5 := m.getHash(string(content))
 		if md5 != mig.Checksum {
-			errors = append(errors, fmt.Sprintf("md5 error for file %s, md5 %s/%s", mig.Migration, md5, mig.Checksum))
+			errorList = append(errorList, fmt.Sprintf("md5 error for file %s, md5 %s/%s", mig.Migration, md5, mig.Checksum))
 		}
 	}
 
-	return errors
+	return errorList
 }
 
-func (m *migration) executeSQLFile(fileName string) (bool, error) {
+func (m *migration) executeSQLFile(file migrationfile.File) (bool, error) {
+	fileName := file.Name
 	exists, err := m.migrationProvider.MigrationExistsForFile(fileName)
 	if err != nil {
 		return false, err
@@ -172,7 +293,7 @@ func (m *migration) executeSQLFile(fileName string) (bool, error) {
 	}
 
 	m.messageDispatch(config.RunningMigrations, fileName)
-	content, err := os.ReadFile(m.migrationFilePath + "/" + fileName)
+	content, err := m.migrationFileManager.ReadFile(file.Source, fileName)
 	if err != nil {
 		return false, err
 	}
@@ -181,7 +302,46 @@ func (m *migration) executeSQLFile(fileName string) (bool, error) {
 	err = m.executeSQL(contentString)
 	if err == nil {
 		hash := m.getHash(contentString)
-		err = m.migrationProvider.AddToMigration(fileName, hash)
+		err = m.migrationProvider.AddToMigration(file.Source, fileName, hash)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	_ = m.migrationProvider.AddToMigrationReport(fileName, err)
+
+	return true, err
+}
+
+// executeRepeatableSQLFile applies a repeatable migration again when its checksum differs from the stored one
+// The previous run is soft deleted, so the history of every applied version is kept
+func (m *migration) executeRepeatableSQLFile(file migrationfile.File) (bool, error) {
+	fileName := file.Name
+	checksum, exists, err := m.migrationProvider.MigrationChecksum(fileName)
+	if err != nil {
+		return false, err
+	}
+
+	content, err := m.migrationFileManager.ReadFile(file.Source, fileName)
+	if err != nil {
+		return false, err
+	}
+
+	contentString := string(content)
+	hash := m.getHash(contentString)
+	if exists && checksum == hash {
+		return false, nil
+	}
+
+	m.messageDispatch(config.RunningMigrations, fileName)
+	err = m.executeSQL(contentString)
+	if err == nil {
+		err = m.migrationProvider.RemoveFromMigration(fileName)
+		if err != nil {
+			return false, err
+		}
+
+		err = m.migrationProvider.AddToMigration(file.Source, fileName, hash)
 		if err != nil {
 			return false, err
 		}
@@ -192,8 +352,29 @@ func (m *migration) executeSQLFile(fileName string) (bool, error) {
 	return true, err
 }
 
-func (m *migration) executeRollbackSQLFile(fileName string) error {
-	rollbackFileName, err := m.migrationFileManager.ResolveRollbackFile(fileName)
+// forgetRepeatableMigrations marks the repeatable migrations as not applied, so they run again after a complete rollback
+func (m *migration) forgetRepeatableMigrations() error {
+	files, err := m.migrationFileManager.OrderedMigrationFiles()
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		if !file.Repeatable {
+			continue
+		}
+
+		err = m.migrationProvider.RemoveFromMigration(file.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *migration) executeRollbackSQLFile(source, fileName string) error {
+	rollbackFileName, err := m.migrationFileManager.ResolveRollbackFile(source, fileName)
 	if err != nil {
 		m.messageDispatch(config.SkipRollback, fileName)
 		return nil
@@ -201,7 +382,7 @@ func (m *migration) executeRollbackSQLFile(fileName string) error {
 
 	m.messageDispatch(config.RunningRollback, rollbackFileName)
 
-	content, err := os.ReadFile(m.migrationFilePath + "/" + rollbackFileName)
+	content, err := m.migrationFileManager.ReadFile(source, rollbackFileName)
 	if err != nil {
 		return err
 	}
@@ -293,11 +474,27 @@ func (m *migration) executeSingleSQL(sql string) error {
 		err = tx.Commit()
 	}()
 
+	if len(m.searchPath) > 0 {
+		_, err = tx.Exec(m.searchPathSQL())
+		if err != nil {
+			return err
+		}
+	}
+
 	_, err = tx.Exec(sql)
 
 	return err
 }
 
+func (m *migration) searchPathSQL() string {
+	schemas := make([]string, len(m.searchPath))
+	for i, schema := range m.searchPath {
+		schemas[i] = dbtypemanager.QuotePostgresIdentifier(schema)
+	}
+
+	return "SET LOCAL search_path TO " + strings.Join(schemas, ", ")
+}
+
 func (m *migration) getHash(sql string) string {
 	hash := md5.Sum([]byte(sql))
 	return hex.EncodeToString(hash[:])
@@ -2,6 +2,7 @@ package migrate
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -14,30 +15,58 @@ const (
 	statusError       = "error"
 	statusSuccess     = "success"
 	reportMessageText = "Created at: %s, File Name: %s, Status: %s, Message: %s\n"
+	seedReportHeader  = "Seeds:\n"
 	timeFormat        = "2006-01-02 15:04:05"
 )
 
 // MigrationProvider is the base migrator interface
 type MigrationProvider interface {
 	Migrations(bool) ([]MigrationRow, error)
-	AddToMigration(string, string) error
+	AddToMigration(source, fileName, checksum string) error
 	RemoveFromMigration(string) error
 	MigrationExistsForFile(string) (bool, error)
+	MigrationChecksum(fileName string) (string, bool, error)
+	BaselineVersion() (string, error)
+	AddBaselineMarker(version string) error
+	AddBaselineMarkerWith(db Execer, version string) error
 	ResetDate()
 	AddToMigrationReport(string, error) error
 	Report() (string, error)
 	CreateMigrationTables() error
 }
 
-// MigrationRow returns with migration file name, and Checksum calculated from the file content
+// Execer executes a statement, on the database or on a transaction
+type Execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}
+
+// ProviderOptions configures the migration provider
+type ProviderOptions struct {
+	Dialect        string
+	TablePrefix    string
+	TrackingSchema TrackingSchema
+	Clock          func() time.Time
+}
+
+// TrackingSchema sets the PostgreSQL schema of the migration tables, an empty Name keeps them in the search_path
+type TrackingSchema struct {
+	Name          string
+	CreateMissing bool
+}
+
+// MigrationRow returns with migration file name, its source and Checksum calculated from the file content
 type MigrationRow struct {
 	Migration string
 	Checksum  string
+	Source    string
 }
 
 type dbMigration struct {
 	db                  *sql.DB
+	dialect             string
 	tablePrefix         string
+	trackingSchema      TrackingSchema
+	clock               func() time.Time
 	timeString          string
 	sqlBindingParameter string
 }
@@ -51,12 +80,12 @@ type reportRow struct {
 
 // NewProvider returns a migration provider, which follows the provider type
 // The provider type can be json or db, error returned if the type incorrectly provided
-// db should be your database *sql.DB, which can be MySQL, Postgres, Sqlite or Firebird
-func NewProvider(tablePrefix string, db *sql.DB) (MigrationProvider, error) {
+// db should be your database *sql.DB, which can be MySQL, Postgres, Sqlite, Firebird or DuckDB
+func NewProvider(db *sql.DB, options ProviderOptions) (MigrationProvider, error) {
 	var dbMigration MigrationProvider
 	var err error
 
-	dbMigration, err = newDbMigration(db, tablePrefix)
+	dbMigration, err = newDbMigration(db, options)
 	if err != nil {
 		return nil, err
 	}
@@ -69,14 +98,43 @@ func NewProvider(tablePrefix string, db *sql.DB) (MigrationProvider, error) {
 	return dbMigration, nil
 }
 
-func newDbMigration(db *sql.DB, tablePrefix string) (*dbMigration, error) {
+// OpenProvider returns a migration provider without creating the migration tables, false if they do not exist
+func OpenProvider(db *sql.DB, options ProviderOptions) (MigrationProvider, bool, error) {
+	dbMigration, err := newDbMigration(db, options)
+	if err != nil {
+		return nil, false, err
+	}
+
+	dbMigration.setSQLBindingParameter(dbMigration.dialect)
+	if !dbMigration.tableExists("migrations") {
+		return nil, false, nil
+	}
+
+	return dbMigration, true, nil
+}
+
+func newDbMigration(db *sql.DB, options ProviderOptions) (*dbMigration, error) {
+	tablePrefix := options.TablePrefix
 	if tablePrefix == "" {
-		tablePrefix = defaultTablePrefix
+		tablePrefix = DefaultTablePrefix
+	}
+
+	// The schema is folded into the prefix, so every %s_migrations reference becomes schema qualified
+	if options.TrackingSchema.Name != "" {
+		tablePrefix = dbtypemanager.QuotePostgresIdentifier(options.TrackingSchema.Name) + "." + tablePrefix
+	}
+
+	clock := options.Clock
+	if clock == nil {
+		clock = time.Now
 	}
 
 	dbMigration := &dbMigration{
-		db:          db,
-		tablePrefix: tablePrefix,
+		db:             db,
+		dialect:        options.Dialect,
+		tablePrefix:    tablePrefix,
+		trackingSchema: options.TrackingSchema,
+		clock:          clock,
 	}
 
 	dbMigration.ResetDate()
@@ -86,12 +144,14 @@ func newDbMigration(db *sql.DB, tablePrefix string) (*dbMigration, error) {
 
 // CreateMigrationTables creates the migration tables
 func (m *dbMigration) CreateMigrationTables() error {
-	driverType, err := dbtypemanager.GetDiverType(m.db)
+	m.setSQLBindingParameter(m.dialect)
+
+	err := m.createTrackingSchema(m.dialect)
 	if err != nil {
 		return err
 	}
-	m.setSQLBindingParameter(driverType)
-	createSQLProvider, err := migrationTableProviderByDriverName(driverType, m.tablePrefix)
+
+	createSQLProvider, err := migrationTableProviderByDriverName(m.dialect, m.tablePrefix)
 	if err != nil {
 		return err
 	}
@@ -104,8 +164,26 @@ func (m *dbMigration) CreateMigrationTables() error {
 	return nil
 }
 
+func (m *dbMigration) createTrackingSchema(driverType string) error {
+	if m.trackingSchema.Name == "" {
+		return nil
+	}
+
+	if driverType != dbtypemanager.DbTypePostgres {
+		return fmt.Errorf("tracking schema is only supported for PostgreSQL, driver: %s", driverType)
+	}
+
+	if !m.trackingSchema.CreateMissing {
+		return nil
+	}
+
+	_, err := m.db.Exec("CREATE SCHEMA IF NOT EXISTS " + dbtypemanager.QuotePostgresIdentifier(m.trackingSchema.Name))
+
+	return err
+}
+
 func (m *dbMigration) ResetDate() {
-	m.timeString = time.Now().Format(timeFormat)
+	m.timeString = m.clock().Format(timeFormat)
 }
 
 func (m *dbMigration) Migrations(isLatest bool) ([]MigrationRow, error) {
@@ -135,11 +213,18 @@ func (m *dbMigration) Migrations(isLatest bool) ([]MigrationRow, error) {
 	defer rows.Close()
 
 	var migration MigrationRow
+	var source sql.NullString
 	for rows.Next() {
-		err := rows.Scan(&migration.Migration, &migration.Checksum)
+		err := rows.Scan(&migration.Migration, &migration.Checksum, &source)
 		if err != nil {
 			return nil, err
 		}
+
+		if !isVersioned(migration.Migration) {
+			continue
+		}
+
+		migration.Source = source.String
 		migrationList = append(migrationList, migration)
 	}
 
@@ -148,7 +233,7 @@ func (m *dbMigration) Migrations(isLatest bool) ([]MigrationRow, error) {
 
 func (m *dbMigration) latestMigrations(lastMigrationDate string) (*sql.Rows, error) {
 	return m.db.Query(fmt.Sprintf(
-		`SELECT file_name, checksum 
+		`SELECT file_name, checksum, source
 		 FROM %s_migrations
 		 WHERE created_at = %s 
 		 AND deleted_at IS NULL
@@ -161,7 +246,7 @@ func (m *dbMigration) latestMigrations(lastMigrationDate string) (*sql.Rows, err
 func (m *dbMigration) allMigrations() (*sql.Rows, error) {
 	return m.db.Query(
 		fmt.Sprintf(
-			`SELECT file_name, checksum 
+			`SELECT file_name, checksum, source
 			FROM %s_migrations
 			WHERE deleted_at IS NULL
 			ORDER BY file_name DESC`,
@@ -170,20 +255,24 @@ func (m *dbMigration) allMigrations() (*sql.Rows, error) {
 	)
 }
 
-func (m *dbMigration) AddToMigration(fileName, checksum string) error {
+func (m *dbMigration) AddToMigration(source, fileName, checksum string) error {
+	return m.addToMigration(m.db, source, fileName, checksum)
+}
+
+func (m *dbMigration) addToMigration(db Execer, source, fileName, checksum string) error {
 	sql := fmt.Sprintf(`INSERT INTO %s_migrations  
-			(file_name, created_at, checksum)
-			VALUES (%s, %s, %s)`,
+			(file_name, created_at, checksum, source)
+			VALUES (%s, %s, %s, %s)`,
 		m.tablePrefix,
 		m.getBindingParameter(1),
 		m.getBindingParameter(2),
 		m.getBindingParameter(3),
+		m.getBindingParameter(4),
 	)
 
-	_, err := m.db.Exec(sql, fileName, m.timeString, checksum)
+	_, err := db.Exec(sql, fileName, m.timeString, checksum, source)
 
 	return err
-
 }
 
 func (m *dbMigration) RemoveFromMigration(fileName string) error {
@@ -227,6 +316,70 @@ func (m *dbMigration) MigrationExistsForFile(fileName string) (bool, error) {
 	return cnt > 0, nil
 }
 
+// MigrationChecksum returns the checksum stored for a migration and whether it is applied
+func (m *dbMigration) MigrationChecksum(fileName string) (string, bool, error) {
+	query := fmt.Sprintf(`SELECT checksum
+			FROM %s_migrations
+			WHERE file_name = %s
+			AND deleted_at IS NULL`,
+		m.tablePrefix,
+		m.getBindingParameter(1),
+	)
+
+	var checksum string
+	err := m.db.QueryRow(query, fileName).Scan(&checksum)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return checksum, true, nil
+}
+
+// BaselineVersion returns the last migration covered by the loaded or squashed baseline, empty if there is none
+func (m *dbMigration) BaselineVersion() (string, error) {
+	rows, err := m.db.Query(
+		fmt.Sprintf(
+			`SELECT file_name
+			FROM %s_migrations
+			WHERE deleted_at IS NULL`,
+			m.tablePrefix,
+		),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	version := ""
+	var fileName string
+	for rows.Next() {
+		err := rows.Scan(&fileName)
+		if err != nil {
+			return "", err
+		}
+
+		if markerVersion, ok := strings.CutPrefix(fileName, BaselineMarkerPrefix); ok && coveredBy(version, markerVersion) {
+			version = markerVersion
+		}
+	}
+
+	return version, rows.Err()
+}
+
+// AddBaselineMarker records that the database contains the schema of every migration up to version
+func (m *dbMigration) AddBaselineMarker(version string) error {
+	return m.AddBaselineMarkerWith(m.db, version)
+}
+
+// AddBaselineMarkerWith records the baseline marker with db, like the transaction loading the baseline
+func (m *dbMigration) AddBaselineMarkerWith(db Execer, version string) error {
+	return m.addToMigration(db, "", BaselineMarkerPrefix+version, "")
+}
+
 func (m *dbMigration) init(createSQLProvider migrationTableSQLProvider) error {
 	sql := createSQLProvider.createMigrationSQL()
 
@@ -235,32 +388,87 @@ func (m *dbMigration) init(createSQLProvider migrationTableSQLProvider) error {
 		return err
 	}
 
+	err = m.addSourceColumn(createSQLProvider)
+	if err != nil {
+		return err
+	}
+
 	sql = createSQLProvider.createReportSQL()
 	_, err = m.db.Exec(sql)
 
 	return err
 }
 
+// addSourceColumn upgrades migration tables created before migration sources were introduced
+func (m *dbMigration) addSourceColumn(createSQLProvider migrationTableSQLProvider) error {
+	rows, err := m.db.Query(fmt.Sprintf("SELECT source FROM %s_migrations WHERE 1 = 0", m.tablePrefix))
+	if err == nil {
+		return rows.Close()
+	}
+
+	_, err = m.db.Exec(createSQLProvider.addSourceColumnSQL())
+
+	return err
+}
+
+// lastMigrationDate returns the batch date of the latest versioned migration, repeatable migrations are not batches
+// Errors of the tracking table are returned, so a broken table is not mistaken for a database without migrations
 func (m *dbMigration) lastMigrationDate() (string, error) {
-	sql := fmt.Sprintf(
+	fileName, err := m.lastVersionedMigration()
+	if err != nil || fileName == "" {
+		return "", err
+	}
+
+	query := fmt.Sprintf(
 		`SELECT max(created_at) as latest_migration
 			FROM %s_migrations
-			WHERE deleted_at IS NULL`,
+			WHERE file_name = %s
+			AND deleted_at IS NULL`,
 		m.tablePrefix,
+		m.getBindingParameter(1),
 	)
 
-	row := m.db.QueryRow(sql)
-	var maxdate string
-	err := row.Scan(&maxdate)
+	var maxdate sql.NullString
+	err = m.db.QueryRow(query, fileName).Scan(&maxdate)
 	if err != nil {
-		return "", nil
+		return "", err
 	}
 
-	return maxdate, err
+	return maxdate.String, nil
+}
+
+func (m *dbMigration) lastVersionedMigration() (string, error) {
+	rows, err := m.db.Query(
+		fmt.Sprintf(
+			`SELECT file_name
+			FROM %s_migrations
+			WHERE deleted_at IS NULL
+			ORDER BY created_at DESC`,
+			m.tablePrefix,
+		),
+	)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+
+	var fileName string
+	for rows.Next() {
+		err := rows.Scan(&fileName)
+		if err != nil {
+			return "", err
+		}
+
+		if isVersioned(fileName) {
+			return fileName, nil
+		}
+	}
+
+	return "", rows.Err()
 }
 
 func (m *dbMigration) setSQLBindingParameter(driverType string) {
-	if driverType == dbtypemanager.DbTypePostgres {
+	if driverType == dbtypemanager.DbTypePostgres || driverType == dbtypemanager.DbTypeDuckDB {
 		m.sqlBindingParameter = "$"
 
 		return
@@ -278,10 +486,15 @@ func (m *dbMigration) getBindingParameter(index int) string {
 }
 
 func (m *dbMigration) AddToMigrationReport(fileName string, errorToLog error) error {
-	sql := fmt.Sprintf(`INSERT INTO %s_migration_reports
+	return m.addReport("migration_reports", fileName, errorToLog)
+}
+
+func (m *dbMigration) addReport(tableSuffix, fileName string, errorToLog error) error {
+	sql := fmt.Sprintf(`INSERT INTO %s_%s
 			(file_name, created_at, result_status, message)
 			VALUES (%s, %s, %s, %s)`,
 		m.tablePrefix,
+		tableSuffix,
 		m.getBindingParameter(1),
 		m.getBindingParameter(2),
 		m.getBindingParameter(3),
@@ -295,18 +508,42 @@ func (m *dbMigration) AddToMigrationReport(fileName string, errorToLog error) er
 		status = statusError
 	}
 
-	createdAt := time.Now().Format(timeFormat)
+	createdAt := m.clock().Format(timeFormat)
 
 	_, err := m.db.Exec(sql, fileName, createdAt, status, message)
 
 	return err
 }
 
+// Report returns the migration audit report, followed by the seed report when seeds were ever run
 func (m *dbMigration) Report() (string, error) {
+	report, err := m.reportLines("migration_reports")
+	if err != nil {
+		return "", err
+	}
+
+	if !m.tableExists("seed_reports") {
+		return report, nil
+	}
+
+	seedReport, err := m.reportLines("seed_reports")
+	if err != nil {
+		return "", err
+	}
+
+	if seedReport == "" {
+		return report, nil
+	}
+
+	return report + seedReportHeader + seedReport, nil
+}
+
+func (m *dbMigration) reportLines(tableSuffix string) (string, error) {
 	rows, err := m.db.Query(
 		fmt.Sprintf(
-			`SELECT file_name, created_at, result_status, message FROM %s_migration_reports`,
+			`SELECT file_name, created_at, result_status, message FROM %s_%s`,
 			m.tablePrefix,
+			tableSuffix,
 		),
 	)
 	if err != nil {
@@ -333,3 +570,12 @@ func (m *dbMigration) Report() (string, error) {
 
 	return builder.String(), nil
 }
+
+func (m *dbMigration) tableExists(tableSuffix string) bool {
+	rows, err := m.db.Query(fmt.Sprintf("SELECT 1 FROM %s_%s WHERE 1 = 0", m.tablePrefix, tableSuffix))
+	if err != nil {
+		return false
+	}
+
+	return rows.Close() == nil
+}
@@ -0,0 +1,56 @@
+package migrate
+
+import "fmt"
+
+type duckDBMigrationTableSQLProvider struct {
+	tablePrefix string
+}
+
+func (p *duckDBMigrationTableSQLProvider) createMigrationSQL() string {
+	sql := `CREATE TABLE IF NOT EXISTS %s_migrations (
+		file_name VARCHAR(255),
+		created_at TIMESTAMP,
+		deleted_at TIMESTAMP,
+		checksum CHAR(32),
+		source VARCHAR(255)
+	)`
+
+	return fmt.Sprintf(sql, p.tablePrefix)
+}
+
+func (p *duckDBMigrationTableSQLProvider) createReportSQL() string {
+	sql := `CREATE TABLE IF NOT EXISTS %s_migration_reports (
+		file_name VARCHAR(255),
+		result_status VARCHAR(12),
+		created_at TIMESTAMP,
+		message TEXT
+	)`
+
+	return fmt.Sprintf(sql, p.tablePrefix)
+}
+
+func (p *duckDBMigrationTableSQLProvider) addSourceColumnSQL() string {
+	return fmt.Sprintf(defaultMigrationAddSourceSQL, p.tablePrefix)
+}
+
+func (p *duckDBMigrationTableSQLProvider) createSeedSQL() string {
+	sql := `CREATE TABLE IF NOT EXISTS %s_seeds (
+		file_name VARCHAR(255),
+		checksum CHAR(32),
+		created_at TIMESTAMP,
+		updated_at TIMESTAMP
+	)`
+
+	return fmt.Sprintf(sql, p.tablePrefix)
+}
+
+func (p *duckDBMigrationTableSQLProvider) createSeedReportSQL() string {
+	sql := `CREATE TABLE IF NOT EXISTS %s_seed_reports (
+		file_name VARCHAR(255),
+		result_status VARCHAR(12),
+		created_at TIMESTAMP,
+		message TEXT
+	)`
+
+	return fmt.Sprintf(sql, p.tablePrefix)
+}
@@ -16,8 +16,9 @@ func (p *firebirdMigrationTableSQLProvider) createMigrationSQL() string {
 		execute statement 'CREATE TABLE %s_MIGRATIONS (
 			file_name VARCHAR(255),
 			created_at VARCHAR(35),
-			deleted_at TIMESTAMP)
-			checksum CHAR(32);';
+			deleted_at TIMESTAMP,
+			checksum CHAR(32),
+			source VARCHAR(255));';
 		END`
 
 	return fmt.Sprintf(sql, upperCasePrefix, upperCasePrefix)
@@ -36,3 +37,35 @@ func (p *firebirdMigrationTableSQLProvider) createReportSQL() string {
 
 	return fmt.Sprintf(sql, upperCasePrefix, upperCasePrefix)
 }
+
+func (p *firebirdMigrationTableSQLProvider) addSourceColumnSQL() string {
+	return fmt.Sprintf("ALTER TABLE %s_MIGRATIONS ADD source VARCHAR(255)", strings.ToUpper(p.tablePrefix))
+}
+
+func (p *firebirdMigrationTableSQLProvider) createSeedSQL() string {
+	upperCasePrefix := strings.ToUpper(p.tablePrefix)
+	sql := `EXECUTE BLOCK AS BEGIN
+		if (not exists(select 1 from rdb$relations where rdb$relation_name = '%s_SEEDS')) then
+		execute statement 'CREATE TABLE %s_SEEDS (
+			file_name VARCHAR(255),
+			checksum CHAR(32),
+			created_at VARCHAR(35),
+			updated_at VARCHAR(35));';
+		END`
+
+	return fmt.Sprintf(sql, upperCasePrefix, upperCasePrefix)
+}
+
+func (p *firebirdMigrationTableSQLProvider) createSeedReportSQL() string {
+	upperCasePrefix := strings.ToUpper(p.tablePrefix)
+	sql := `EXECUTE BLOCK AS BEGIN
+		if (not exists(select 1 from rdb$relations where rdb$relation_name = '%s_SEED_REPORTS')) then
+		execute statement 'CREATE TABLE %s_SEED_REPORTS (
+			file_name VARCHAR(255),
+			result_status VARCHAR(12),
+			created_at VARCHAR(35),
+			message BLOB SUB_TYPE TEXT);';
+		END`
+
+	return fmt.Sprintf(sql, upperCasePrefix, upperCasePrefix)
+}
@@ -13,3 +13,15 @@ func (p *mySQLMigrationTableSQLProvider) createMigrationSQL() string {
 func (p *mySQLMigrationTableSQLProvider) createReportSQL() string {
 	return fmt.Sprintf(defaultMigrationReportCreateTableSQL, p.tablePrefix)
 }
+
+func (p *mySQLMigrationTableSQLProvider) addSourceColumnSQL() string {
+	return fmt.Sprintf(defaultMigrationAddSourceSQL, p.tablePrefix)
+}
+
+func (p *mySQLMigrationTableSQLProvider) createSeedSQL() string {
+	return fmt.Sprintf(defaultSeedCreateTableSQL, p.tablePrefix)
+}
+
+func (p *mySQLMigrationTableSQLProvider) createSeedReportSQL() string {
+	return fmt.Sprintf(defaultSeedReportCreateTableSQL, p.tablePrefix)
+}
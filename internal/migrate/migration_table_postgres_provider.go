@@ -11,7 +11,8 @@ func (p *postgresMigrationTableSQLProvider) createMigrationSQL() string {
 		file_name VARCHAR(255),
 		created_at TIMESTAMP,
 		deleted_at TIMESTAMP,
-		checksum CHAR(32)
+		checksum CHAR(32),
+		source VARCHAR(255)
 	)`
 
 	return fmt.Sprintf(sql, p.tablePrefix)
@@ -27,3 +28,29 @@ func (p *postgresMigrationTableSQLProvider) createReportSQL() string {
 
 	return fmt.Sprintf(sql, p.tablePrefix)
 }
+
+func (p *postgresMigrationTableSQLProvider) addSourceColumnSQL() string {
+	return fmt.Sprintf(defaultMigrationAddSourceSQL, p.tablePrefix)
+}
+
+func (p *postgresMigrationTableSQLProvider) createSeedSQL() string {
+	sql := `CREATE TABLE IF NOT EXISTS %s_seeds (
+		file_name VARCHAR(255),
+		checksum CHAR(32),
+		created_at TIMESTAMP,
+		updated_at TIMESTAMP
+	)`
+
+	return fmt.Sprintf(sql, p.tablePrefix)
+}
+
+func (p *postgresMigrationTableSQLProvider) createSeedReportSQL() string {
+	sql := `CREATE TABLE IF NOT EXISTS %s_seed_reports (
+		file_name VARCHAR(255),
+		result_status VARCHAR(12),
+		created_at TIMESTAMP,
+		message TEXT
+	)`
+
+	return fmt.Sprintf(sql, p.tablePrefix)
+}
@@ -6,8 +6,10 @@ import (
 	"github.com/olbrichattila/godbmigrator/internal/dbtypemanager"
 )
 
+// DefaultTablePrefix is used when the table prefix is empty
+const DefaultTablePrefix = "olb"
+
 const (
-	defaultTablePrefix                   = "olb"
 	defaultMigrationReportCreateTableSQL = `CREATE TABLE IF NOT EXISTS %s_migration_reports (
 		file_name VARCHAR(255),
 		result_status VARCHAR(12),
@@ -18,13 +20,32 @@ const (
 		file_name VARCHAR(255),
 		created_at DATETIME,
 		deleted_at DATETIME,
-		checksum CHAR(32)
+		checksum CHAR(32),
+		source VARCHAR(255)
+		)`
+
+	defaultMigrationAddSourceSQL = `ALTER TABLE %s_migrations ADD COLUMN source VARCHAR(255)`
+
+	defaultSeedCreateTableSQL = `CREATE TABLE IF NOT EXISTS %s_seeds (
+		file_name VARCHAR(255),
+		checksum CHAR(32),
+		created_at DATETIME,
+		updated_at DATETIME
 		)`
+
+	defaultSeedReportCreateTableSQL = `CREATE TABLE IF NOT EXISTS %s_seed_reports (
+		file_name VARCHAR(255),
+		result_status VARCHAR(12),
+		created_at DATETIME,
+		message TEXT)`
 )
 
 type migrationTableSQLProvider interface {
 	createMigrationSQL() string
 	createReportSQL() string
+	addSourceColumnSQL() string
+	createSeedSQL() string
+	createSeedReportSQL() string
 }
 
 func migrationTableProviderByDriverName(driverName, tablePrefix string) (migrationTableSQLProvider, error) {
@@ -37,6 +58,8 @@ func migrationTableProviderByDriverName(driverName, tablePrefix string) (migrati
 		return &mySQLMigrationTableSQLProvider{tablePrefix: tablePrefix}, nil
 	case dbtypemanager.DbTypeFirebird:
 		return &firebirdMigrationTableSQLProvider{tablePrefix: tablePrefix}, nil
+	case dbtypemanager.DbTypeDuckDB:
+		return &duckDBMigrationTableSQLProvider{tablePrefix: tablePrefix}, nil
 	default:
 		return nil, fmt.Errorf("provider %s does not exists", driverName)
 	}
@@ -13,3 +13,15 @@ func (p *sqliteMigrationTableSQLProvider) createMigrationSQL() string {
 func (p *sqliteMigrationTableSQLProvider) createReportSQL() string {
 	return fmt.Sprintf(defaultMigrationReportCreateTableSQL, p.tablePrefix)
 }
+
+func (p *sqliteMigrationTableSQLProvider) addSourceColumnSQL() string {
+	return fmt.Sprintf(defaultMigrationAddSourceSQL, p.tablePrefix)
+}
+
+func (p *sqliteMigrationTableSQLProvider) createSeedSQL() string {
+	return fmt.Sprintf(defaultSeedCreateTableSQL, p.tablePrefix)
+}
+
+func (p *sqliteMigrationTableSQLProvider) createSeedReportSQL() string {
+	return fmt.Sprintf(defaultSeedReportCreateTableSQL, p.tablePrefix)
+}
@@ -0,0 +1,92 @@
+package migrate
+
+import (
+	"strconv"
+
+	"github.com/olbrichattila/godbmigrator/config"
+	"github.com/olbrichattila/godbmigrator/internal/migrationfile"
+)
+
+// Seed runs the seed files in file name order, a seed runs once unless it is marked rerun-on-change and its content changed
+// fresh forgets the already run seeds first, so every applicable seed runs again
+func (m *migration) Seed(
+	seedProvider SeedProvider,
+	seedFileManager migrationfile.Manager,
+	fresh bool,
+	scope migrationfile.Scope,
+) error {
+	if fresh {
+		err := seedProvider.RemoveSeeds()
+		if err != nil {
+			return err
+		}
+	}
+
+	files, err := seedFileManager.OrderedMigrationFiles()
+	if err != nil {
+		return err
+	}
+
+	seedCount := 0
+	for _, file := range files {
+		if !scope.Applicable(file) {
+			m.messageDispatch(config.SkipNotApplicable, file.Name)
+			continue
+		}
+
+		seeded, err := m.executeSeedFile(seedProvider, seedFileManager, file)
+		if err != nil {
+			return err
+		}
+
+		if seeded {
+			seedCount++
+		}
+	}
+
+	m.messageDispatch(config.SeededItems, strconv.Itoa(seedCount))
+
+	return nil
+}
+
+func (m *migration) executeSeedFile(
+	seedProvider SeedProvider,
+	seedFileManager migrationfile.Manager,
+	file migrationfile.File,
+) (bool, error) {
+	content, err := seedFileManager.ReadFile(file.Source, file.Name)
+	if err != nil {
+		return false, err
+	}
+
+	contentString := string(content)
+	hash := m.getHash(contentString)
+
+	checksum, exists, err := seedProvider.SeedChecksum(file.Name)
+	if err != nil {
+		return false, err
+	}
+
+	if exists && (!file.RerunOnChange || checksum == hash) {
+		return false, nil
+	}
+
+	m.messageDispatch(config.RunningSeed, file.Name)
+
+	err = m.executeSQL(contentString)
+	if err == nil {
+		if exists {
+			err = seedProvider.UpdateSeed(file.Name, hash)
+		} else {
+			err = seedProvider.AddSeed(file.Name, hash)
+		}
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	_ = seedProvider.AddToSeedReport(file.Name, err)
+
+	return true, err
+}
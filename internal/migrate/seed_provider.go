@@ -0,0 +1,119 @@
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SeedProvider stores which seeds were run and the checksum of their content
+type SeedProvider interface {
+	SeedChecksum(fileName string) (string, bool, error)
+	AddSeed(fileName, checksum string) error
+	UpdateSeed(fileName, checksum string) error
+	RemoveSeeds() error
+	AddToSeedReport(fileName string, errorToLog error) error
+	CreateSeedTables() error
+}
+
+// NewSeedProvider returns a seed provider and creates the <prefix>_seeds and <prefix>_seed_reports tables
+func NewSeedProvider(db *sql.DB, options ProviderOptions) (SeedProvider, error) {
+	seedProvider, err := newDbMigration(db, options)
+	if err != nil {
+		return nil, err
+	}
+
+	err = seedProvider.CreateSeedTables()
+	if err != nil {
+		return nil, err
+	}
+
+	return seedProvider, nil
+}
+
+// CreateSeedTables creates the seed tables
+func (m *dbMigration) CreateSeedTables() error {
+	m.setSQLBindingParameter(m.dialect)
+
+	err := m.createTrackingSchema(m.dialect)
+	if err != nil {
+		return err
+	}
+
+	createSQLProvider, err := migrationTableProviderByDriverName(m.dialect, m.tablePrefix)
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.Exec(createSQLProvider.createSeedSQL())
+	if err != nil {
+		return err
+	}
+
+	_, err = m.db.Exec(createSQLProvider.createSeedReportSQL())
+
+	return err
+}
+
+// SeedChecksum returns the stored checksum of a seed and whether it was run before
+func (m *dbMigration) SeedChecksum(fileName string) (string, bool, error) {
+	query := fmt.Sprintf(`SELECT checksum
+			FROM %s_seeds
+			WHERE file_name = %s`,
+		m.tablePrefix,
+		m.getBindingParameter(1),
+	)
+
+	var checksum string
+	err := m.db.QueryRow(query, fileName).Scan(&checksum)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+
+	if err != nil {
+		return "", false, err
+	}
+
+	return checksum, true, nil
+}
+
+func (m *dbMigration) AddSeed(fileName, checksum string) error {
+	sql := fmt.Sprintf(`INSERT INTO %s_seeds
+			(file_name, checksum, created_at, updated_at)
+			VALUES (%s, %s, %s, %s)`,
+		m.tablePrefix,
+		m.getBindingParameter(1),
+		m.getBindingParameter(2),
+		m.getBindingParameter(3),
+		m.getBindingParameter(4),
+	)
+
+	_, err := m.db.Exec(sql, fileName, checksum, m.timeString, m.timeString)
+
+	return err
+}
+
+func (m *dbMigration) UpdateSeed(fileName, checksum string) error {
+	sql := fmt.Sprintf(`UPDATE %s_seeds
+			SET checksum = %s, updated_at = %s
+			WHERE file_name = %s`,
+		m.tablePrefix,
+		m.getBindingParameter(1),
+		m.getBindingParameter(2),
+		m.getBindingParameter(3),
+	)
+
+	_, err := m.db.Exec(sql, checksum, m.timeString, fileName)
+
+	return err
+}
+
+func (m *dbMigration) RemoveSeeds() error {
+	_, err := m.db.Exec(fmt.Sprintf("DELETE FROM %s_seeds", m.tablePrefix))
+
+	return err
+}
+
+func (m *dbMigration) AddToSeedReport(fileName string, errorToLog error) error {
+	return m.addReport("seed_reports", fileName, errorToLog)
+}
@@ -3,24 +3,56 @@ package migrationfile
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"regexp"
 	"sort"
 	"strings"
 	"time"
-
-	"github.com/olbrichattila/godbmigrator/internal/helper"
 )
 
 const (
 	sqlFileExt = ".sql"
+	// ArchiveFolder receives the squashed migration files, it is never part of the migration timeline
+	ArchiveFolder = "squashed"
+	// RepeatablePrefix starts the file name of repeatable migrations, like R__views.sql
+	RepeatablePrefix = "R__"
+	// SeedFolder is the default seed folder inside the migration folder, it is never part of the migration timeline
+	SeedFolder = "seeds"
 )
 
 // Manager encapsulates the migration file management methods
 type Manager interface {
 	CreateNewMigrationFiles(migrationFilePath, customText string) ([]string, error)
-	ResolveRollbackFile(migrationFileName string) (string, error)
-	OrderedMigrationFiles() ([]string, error)
+	CreateMigrationFilesWithContent(migrationFilePath, customText, migration, rollback string) ([]string, error)
+	ResolveRollbackFile(source, migrationFileName string) (string, error)
+	OrderedMigrationFiles() ([]File, error)
+	ResolveFile(source, fileName string) (File, error)
+	ReadFile(source, fileName string) ([]byte, error)
+	ArchiveFiles(migrationFilePath string, files []File) error
+}
+
+// Source is a named set of migration files, the default source has an empty name
+type Source struct {
+	Name string
+	FS   fs.FS
+}
+
+// File is a migration file and the source it was found in, Environments and Tags restrict where it is applicable
+// RerunOnChange marks seeds which run again when their content changes, Repeatable marks R__ migrations
+type File struct {
+	Source        string
+	Name          string
+	Environments  []string
+	Tags          []string
+	RerunOnChange bool
+	Repeatable    bool
+}
+
+// IsRepeatable tells if the migration file is a repeatable migration, which is applied again whenever it changes
+func IsRepeatable(fileName string) bool {
+	return strings.HasPrefix(path.Base(fileName), RepeatablePrefix)
 }
 
 const (
@@ -29,26 +61,47 @@ const (
 	rollbackReplaceRegex  = "^.*\\.sql$"
 )
 
-// New returns with a new file manager instance
-func New(migrationFilePath string) Manager {
+// New returns with a new file manager instance, migration files are read from the sources, new files are created in migrationFilePath
+func New(migrationFilePath string, clock func() time.Time, sources ...Source) Manager {
 	return &mFile{
 		migrationFilePath: migrationFilePath,
+		sources:           sources,
+		clock:             clock,
+	}
+}
+
+// NewSeedManager returns a file manager of seed files, every .sql file of the sources is a seed
+// Unlike migrations, seeds have no rollback files and their names may contain baseline
+func NewSeedManager(migrationFilePath string, clock func() time.Time, sources ...Source) Manager {
+	return &mFile{
+		migrationFilePath: migrationFilePath,
+		sources:           sources,
+		clock:             clock,
+		seeds:             true,
 	}
 }
 
 type mFile struct {
 	migrationFilePath string
+	sources           []Source
+	clock             func() time.Time
+	seeds             bool
 }
 
 // CreateNewMigrationFiles responsible for creating migration files
 func (m *mFile) CreateNewMigrationFiles(migrationFilePath, customText string) ([]string, error) {
-	datePart := time.Now().Format("2006-01-02_15_04_05")
-	file1, err := m.createNewMigrationFile(migrationFilePath, customText, datePart, false)
+	return m.CreateMigrationFilesWithContent(migrationFilePath, customText, "", "")
+}
+
+// CreateMigrationFilesWithContent creates a migration and a rollback file with the given SQL
+func (m *mFile) CreateMigrationFilesWithContent(migrationFilePath, customText, migration, rollback string) ([]string, error) {
+	datePart := m.clock().Format("2006-01-02_15_04_05")
+	file1, err := m.createNewMigrationFile(migrationFilePath, customText, datePart, migration, false)
 	if err != nil {
 		return nil, err
 	}
 
-	file2, err := m.createNewMigrationFile(migrationFilePath, customText, datePart, true)
+	file2, err := m.createNewMigrationFile(migrationFilePath, customText, datePart, rollback, true)
 	if err != nil {
 		return nil, err
 	}
@@ -57,7 +110,7 @@ func (m *mFile) CreateNewMigrationFiles(migrationFilePath, customText string) ([
 
 }
 
-func (*mFile) createNewMigrationFile(migrationFilePath, customText, datePart string, isRollback bool) (string, error) {
+func (*mFile) createNewMigrationFile(migrationFilePath, customText, datePart, content string, isRollback bool) (string, error) {
 	suffix := ""
 	prefix := ""
 
@@ -83,10 +136,19 @@ func (*mFile) createNewMigrationFile(migrationFilePath, customText, datePart str
 	}
 	defer file.Close()
 
+	_, err = file.WriteString(content)
+	if err != nil {
+		return "", err
+	}
+
 	return filePath, nil
 }
 
-func (m *mFile) ResolveRollbackFile(migrationFileName string) (string, error) {
+func (m *mFile) ResolveRollbackFile(source, migrationFileName string) (string, error) {
+	fsys, err := m.sourceFS(source)
+	if err != nil {
+		return "", err
+	}
 
 	lastIndex := strings.LastIndex(migrationFileName, sqlFileExt)
 	if lastIndex == -1 {
@@ -94,33 +156,239 @@ func (m *mFile) ResolveRollbackFile(migrationFileName string) (string, error) {
 	}
 
 	rollbackFile := migrationFileName[:lastIndex] + "-rollback.sql"
-	if !helper.FileExists(m.migrationFilePath + "/" + rollbackFile) {
+	if _, err := fs.Stat(fsys, rollbackFile); err != nil {
 		return "", fmt.Errorf("file does not %s exists", rollbackFile)
 	}
 
 	return rollbackFile, nil
 }
 
-func (m *mFile) OrderedMigrationFiles() ([]string, error) {
-	files, err := os.ReadDir(m.migrationFilePath)
+// OrderedMigrationFiles merges the migration files of all sources into one timeline ordered by file name
+// The same file name in two sources is an error, as the migration table identifies migrations by file name
+// Files in env.<name> and tag.<name> sub folders are included with the environment or tag of the folder
+// Repeatable migrations are ordered after all versioned migrations
+func (m *mFile) OrderedMigrationFiles() ([]File, error) {
+	var migrationFiles []File
+	// Files are keyed by base name, a file of an env or tag sub folder is recorded without its folder
+	fileSources := make(map[string]File)
+	for _, source := range m.sources {
+		files, err := m.sourceMigrationFiles(source)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range files {
+			baseName := path.Base(file.Name)
+			if existing, ok := fileSources[baseName]; ok {
+				return nil, fmt.Errorf(
+					"duplicate migration file %s in sources %s (%s) and %s (%s)",
+					baseName,
+					m.sourceDisplayName(existing.Source),
+					existing.Name,
+					m.sourceDisplayName(source.Name),
+					file.Name,
+				)
+			}
+
+			fileSources[baseName] = file
+			migrationFiles = append(migrationFiles, file)
+		}
+	}
+
+	sort.Slice(migrationFiles, func(i, j int) bool {
+		if migrationFiles[i].Repeatable != migrationFiles[j].Repeatable {
+			return !migrationFiles[i].Repeatable
+		}
+
+		iBase, jBase := path.Base(migrationFiles[i].Name), path.Base(migrationFiles[j].Name)
+		if iBase != jBase {
+			return iBase < jBase
+		}
+
+		return migrationFiles[i].Name < migrationFiles[j].Name
+	})
+
+	return migrationFiles, nil
+}
+
+func (m *mFile) sourceMigrationFiles(source Source) ([]File, error) {
+	entries, err := fs.ReadDir(source.FS, ".")
+	if err != nil {
+		return nil, fmt.Errorf("cannot read migration source %s, error: %w", m.sourceDisplayName(source.Name), err)
+	}
+
+	var files []File
+	for _, entry := range entries {
+		if entry.IsDir() && entry.Name() == SeedFolder {
+			continue
+		}
+
+		if entry.IsDir() {
+			folderFiles, err := m.scopedFolderFiles(source, entry.Name())
+			if err != nil {
+				return nil, err
+			}
+			files = append(files, folderFiles...)
+			continue
+		}
+
+		if !m.isListed(entry.Name()) {
+			continue
+		}
+
+		file, err := m.newFile(source, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func (m *mFile) scopedFolderFiles(source Source, folder string) ([]File, error) {
+	kind, name, ok := scopeFolder(folder)
+	if !ok {
+		return nil, nil
+	}
+
+	entries, err := fs.ReadDir(source.FS, folder)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read migration folder %s, error: %w", folder, err)
+	}
+
+	var files []File
+	for _, entry := range entries {
+		if entry.IsDir() || !m.isListed(entry.Name()) {
+			continue
+		}
+
+		file, err := m.newFile(source, folder+"/"+entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if kind == scopeFolderEnvironment {
+			file.Environments = append(file.Environments, name)
+		} else {
+			file.Tags = append(file.Tags, name)
+		}
+		files = append(files, file)
+	}
+
+	return files, nil
+}
+
+func (m *mFile) newFile(source Source, fileName string) (File, error) {
+	content, err := fs.ReadFile(source.FS, fileName)
+	if err != nil {
+		return File{}, err
+	}
+
+	fileAnnotations := parseAnnotations(string(content))
+	file := File{
+		Source:        source.Name,
+		Name:          fileName,
+		Environments:  fileAnnotations.environments,
+		Tags:          fileAnnotations.tags,
+		RerunOnChange: fileAnnotations.rerunOnChange,
+		Repeatable:    IsRepeatable(fileName),
+	}
+
+	return file, nil
+}
+
+// ResolveFile returns a migration file with its environments and tags
+func (m *mFile) ResolveFile(source, fileName string) (File, error) {
+	fsys, err := m.sourceFS(source)
+	if err != nil {
+		return File{}, err
+	}
+
+	file, err := m.newFile(Source{Name: source, FS: fsys}, fileName)
+	if err != nil {
+		return File{}, err
+	}
+
+	if kind, name, ok := scopeFolder(path.Dir(fileName)); ok {
+		if kind == scopeFolderEnvironment {
+			file.Environments = append(file.Environments, name)
+		} else {
+			file.Tags = append(file.Tags, name)
+		}
+	}
+
+	return file, nil
+}
+
+// ReadFile returns the content of a migration or rollback file from the given source
+func (m *mFile) ReadFile(source, fileName string) ([]byte, error) {
+	fsys, err := m.sourceFS(source)
 	if err != nil {
 		return nil, err
 	}
 
-	var fileNames []string
+	return fs.ReadFile(fsys, fileName)
+}
+
+// ArchiveFiles moves the migration files and their rollback files into the archive folder of migrationFilePath
+func (m *mFile) ArchiveFiles(migrationFilePath string, files []File) error {
 	for _, file := range files {
-		fileName := file.Name()
-		if strings.HasSuffix(fileName, sqlFileExt) && m.isMigration(fileName) {
-			fileNames = append(fileNames, fileName)
+		fileNames := []string{file.Name}
+		rollbackFileName, err := m.ResolveRollbackFile(file.Source, file.Name)
+		if err == nil {
+			fileNames = append(fileNames, rollbackFileName)
+		}
+
+		for _, fileName := range fileNames {
+			archivePath := path.Join(migrationFilePath, ArchiveFolder, fileName)
+			err = os.MkdirAll(path.Dir(archivePath), 0o755)
+			if err != nil {
+				return fmt.Errorf("cannot create archive folder for %s, error: %w", fileName, err)
+			}
+
+			err = os.Rename(path.Join(migrationFilePath, fileName), archivePath)
+			if err != nil {
+				return fmt.Errorf("cannot archive migration file %s, error: %w", fileName, err)
+			}
 		}
 	}
 
-	sort.Strings(fileNames)
+	return nil
+}
 
-	return fileNames, nil
+func (m *mFile) sourceFS(name string) (fs.FS, error) {
+	for _, source := range m.sources {
+		if source.Name == name {
+			return source.FS, nil
+		}
+	}
+
+	return nil, fmt.Errorf("migration source %s is not registered", m.sourceDisplayName(name))
+}
+
+func (*mFile) sourceDisplayName(name string) string {
+	if name == "" {
+		return "default"
+	}
+
+	return name
+}
+
+// isListed tells if the file is one of the files the manager orders, a migration or a seed
+func (m *mFile) isListed(fileName string) bool {
+	if m.seeds {
+		return strings.HasSuffix(fileName, sqlFileExt)
+	}
+
+	return m.isMigration(fileName)
 }
 
 func (m *mFile) isMigration(fileName string) bool {
+	if !strings.HasSuffix(fileName, sqlFileExt) {
+		return false
+	}
+
 	if strings.Contains(strings.ToLower(fileName), strings.ToLower("baseline")) {
 		return false
 	}
@@ -0,0 +1,108 @@
+package migrationfile
+
+import (
+	"bufio"
+	"strings"
+)
+
+const (
+	annotationEnvironment   = "-- migrator:env"
+	annotationTags          = "-- migrator:tags"
+	annotationRerunOnChange = "-- migrator:rerun-on-change"
+	scopeFolderEnvironment  = "env"
+	scopeFolderTag          = "tag"
+)
+
+// Scope is the active environment and tag set, files not matching it are not applicable
+type Scope struct {
+	Environment string
+	Tags        []string
+}
+
+// Applicable tells if the file should run in the scope
+// A file restricted to environments runs only in one of them, a tagged file runs only if one of its tags is active
+func (s Scope) Applicable(file File) bool {
+	if len(file.Environments) > 0 && !contains(file.Environments, s.Environment) {
+		return false
+	}
+
+	if len(file.Tags) == 0 {
+		return true
+	}
+
+	for _, tag := range file.Tags {
+		if contains(s.Tags, tag) {
+			return true
+		}
+	}
+
+	return false
+}
+
+type annotations struct {
+	environments  []string
+	tags          []string
+	rerunOnChange bool
+}
+
+// parseAnnotations reads the leading comment block of a migration or seed, like:
+// -- migrator:env development, test
+// -- migrator:tags fixtures
+// -- migrator:rerun-on-change
+func parseAnnotations(content string) annotations {
+	var result annotations
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "--") {
+			break
+		}
+
+		if value, ok := strings.CutPrefix(line, annotationEnvironment); ok {
+			result.environments = append(result.environments, splitAnnotationValues(value)...)
+			continue
+		}
+
+		if value, ok := strings.CutPrefix(line, annotationTags); ok {
+			result.tags = append(result.tags, splitAnnotationValues(value)...)
+			continue
+		}
+
+		if line == annotationRerunOnChange {
+			result.rerunOnChange = true
+		}
+	}
+
+	return result
+}
+
+func splitAnnotationValues(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+// scopeFolder recognizes the env.<name> and tag.<name> sub folder convention
+func scopeFolder(folder string) (string, string, bool) {
+	kind, name, ok := strings.Cut(folder, ".")
+	if !ok || name == "" || (kind != scopeFolderEnvironment && kind != scopeFolderTag) {
+		return "", "", false
+	}
+
+	return kind, name, true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,141 @@
+package schemadiff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/olbrichattila/godbmigrator/internal/baseliner"
+)
+
+const (
+	mySQLPartColumn     = "column"
+	mySQLPartPrimaryKey = "primary key"
+	mySQLPartIndex      = "index"
+	mySQLPartForeignKey = "foreign key"
+	mySQLPartCheck      = "check"
+)
+
+var (
+	definerRegex         = regexp.MustCompile("(?i)\\s+DEFINER\\s*=\\s*(`[^`]*`|[^\\s@]+)@(`[^`]*`|\\S+)")
+	mySQLIndexRegex      = regexp.MustCompile("^(?:UNIQUE |FULLTEXT |SPATIAL )?KEY `([^`]+)`")
+	mySQLConstraintRegex = regexp.MustCompile("^CONSTRAINT `([^`]+)` (FOREIGN KEY|CHECK)")
+)
+
+// mySQLTablePart is a line of SHOW CREATE TABLE, a column, a key or a constraint
+type mySQLTablePart struct {
+	kind       string
+	name       string
+	definition string
+}
+
+type mySQLDDL struct{}
+
+// createObject removes the DEFINER clause, so the migration runs with the privileges of the migrating user
+func (*mySQLDDL) createObject(object baseliner.Object) string {
+	return definerRegex.ReplaceAllString(object.SQL, "")
+}
+
+// dropObject drops the object, constraints deferred out of a table in a reference cycle are named <table>.<name>
+func (m *mySQLDDL) dropObject(object baseliner.Object) string {
+	if i := strings.LastIndex(object.Name, "."); object.Type == baseliner.ObjectTypeConstraint && i > 0 {
+		return fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", m.quote(object.Name[:i]), m.quote(object.Name[i+1:]))
+	}
+
+	return fmt.Sprintf("DROP %s %s", strings.ToUpper(object.Type), m.quote(object.Name))
+}
+
+// alterTable compares the lines of SHOW CREATE TABLE, which lists every column, key and constraint on its own line
+func (m *mySQLDDL) alterTable(from, to baseliner.Object, _, _ baseliner.Baseliner) ([]string, bool, error) {
+	fromParts := m.tableParts(from.SQL)
+	toParts := m.tableParts(to.SQL)
+	table := m.quote(to.Name)
+
+	var drops, columns, adds []string
+	for _, part := range fromParts {
+		target, ok := findPart(toParts, part)
+		if ok && (part.kind == mySQLPartColumn || target.definition == part.definition) {
+			continue
+		}
+
+		switch part.kind {
+		case mySQLPartColumn:
+			columns = append(columns, fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s", table, m.quote(part.name)))
+		case mySQLPartPrimaryKey:
+			drops = append(drops, fmt.Sprintf("ALTER TABLE %s DROP PRIMARY KEY", table))
+		case mySQLPartIndex:
+			drops = append(drops, fmt.Sprintf("ALTER TABLE %s DROP INDEX %s", table, m.quote(part.name)))
+		case mySQLPartForeignKey:
+			drops = append([]string{fmt.Sprintf("ALTER TABLE %s DROP FOREIGN KEY %s", table, m.quote(part.name))}, drops...)
+		case mySQLPartCheck:
+			drops = append(drops, fmt.Sprintf("ALTER TABLE %s DROP CHECK %s", table, m.quote(part.name)))
+		}
+	}
+
+	for _, part := range toParts {
+		source, ok := findPart(fromParts, part)
+		if ok && source.definition == part.definition {
+			continue
+		}
+
+		switch {
+		case part.kind == mySQLPartColumn && ok:
+			columns = append(columns, fmt.Sprintf("ALTER TABLE %s MODIFY COLUMN %s", table, part.definition))
+		case part.kind == mySQLPartColumn:
+			columns = append(columns, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", table, part.definition))
+		case part.kind == mySQLPartForeignKey:
+			adds = append(adds, fmt.Sprintf("ALTER TABLE %s ADD %s", table, part.definition))
+		default:
+			adds = append([]string{fmt.Sprintf("ALTER TABLE %s ADD %s", table, part.definition)}, adds...)
+		}
+	}
+
+	return append(append(drops, columns...), adds...), false, nil
+}
+
+// tableParts splits SHOW CREATE TABLE into its column, key and constraint lines, table options are ignored
+func (*mySQLDDL) tableParts(createSQL string) []mySQLTablePart {
+	var parts []mySQLTablePart
+	for _, line := range strings.Split(createSQL, "\n") {
+		definition := strings.TrimSuffix(strings.TrimSpace(line), ",")
+
+		switch {
+		case strings.HasPrefix(definition, "`"):
+			name, _, _ := strings.Cut(definition[1:], "`")
+			parts = append(parts, mySQLTablePart{kind: mySQLPartColumn, name: name, definition: definition})
+		case strings.HasPrefix(definition, "PRIMARY KEY"):
+			parts = append(parts, mySQLTablePart{kind: mySQLPartPrimaryKey, definition: definition})
+		case mySQLIndexRegex.MatchString(definition):
+			name := mySQLIndexRegex.FindStringSubmatch(definition)[1]
+			parts = append(parts, mySQLTablePart{kind: mySQLPartIndex, name: name, definition: definition})
+		case mySQLConstraintRegex.MatchString(definition):
+			matches := mySQLConstraintRegex.FindStringSubmatch(definition)
+			kind := mySQLPartCheck
+			if matches[2] == "FOREIGN KEY" {
+				kind = mySQLPartForeignKey
+			}
+			parts = append(parts, mySQLTablePart{kind: kind, name: matches[1], definition: definition})
+		}
+	}
+
+	return parts
+}
+
+// exclude leaves nothing out, the migration tables are excluded by the caller
+func (*mySQLDDL) exclude(baseliner.Object) bool {
+	return false
+}
+
+func (*mySQLDDL) quote(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func findPart(parts []mySQLTablePart, part mySQLTablePart) (mySQLTablePart, bool) {
+	for _, candidate := range parts {
+		if candidate.kind == part.kind && candidate.name == part.name {
+			return candidate, true
+		}
+	}
+
+	return mySQLTablePart{}, false
+}
@@ -0,0 +1,161 @@
+package schemadiff
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/olbrichattila/godbmigrator/internal/baseliner"
+)
+
+func TestMySQLDropObject(t *testing.T) {
+	cases := []struct {
+		object   baseliner.Object
+		expected string
+	}{
+		{baseliner.Object{Type: baseliner.ObjectTypeView, Name: "user_names"}, "DROP VIEW `user_names`"},
+		{baseliner.Object{Type: baseliner.ObjectTypeTable, Name: "users"}, "DROP TABLE `users`"},
+		{baseliner.Object{Type: baseliner.ObjectTypeConstraint, Name: "posts.fk_posts_users"}, "ALTER TABLE `posts` DROP FOREIGN KEY `fk_posts_users`"},
+	}
+
+	ddl := &mySQLDDL{}
+	for _, c := range cases {
+		if statement := ddl.dropObject(c.object); statement != c.expected {
+			t.Errorf("drop of %s %s is %q, expected %q", c.object.Type, c.object.Name, statement, c.expected)
+		}
+	}
+}
+
+// The table changes are tested on SHOW CREATE TABLE fixtures here, the test module has no MySQL server to generate them
+const mySQLPostsTable = "CREATE TABLE `posts` (\n" +
+	"  `id` int NOT NULL AUTO_INCREMENT,\n" +
+	"  `user_id` int NOT NULL,\n" +
+	"  `title` varchar(100) NOT NULL,\n" +
+	"  PRIMARY KEY (`id`),\n" +
+	"  KEY `idx_posts_title` (`title`),\n" +
+	"  CONSTRAINT `fk_posts_users` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)\n" +
+	") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4"
+
+func TestMySQLTableParts(t *testing.T) {
+	expected := []mySQLTablePart{
+		{kind: mySQLPartColumn, name: "id", definition: "`id` int NOT NULL AUTO_INCREMENT"},
+		{kind: mySQLPartColumn, name: "user_id", definition: "`user_id` int NOT NULL"},
+		{kind: mySQLPartColumn, name: "title", definition: "`title` varchar(100) NOT NULL"},
+		{kind: mySQLPartPrimaryKey, definition: "PRIMARY KEY (`id`)"},
+		{kind: mySQLPartIndex, name: "idx_posts_title", definition: "KEY `idx_posts_title` (`title`)"},
+		{kind: mySQLPartForeignKey, name: "fk_posts_users", definition: "CONSTRAINT `fk_posts_users` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)"},
+	}
+
+	parts := (&mySQLDDL{}).tableParts(mySQLPostsTable)
+	if len(parts) != len(expected) {
+		t.Fatalf("table has %d parts, expected %d: %v", len(parts), len(expected), parts)
+	}
+
+	for i, part := range parts {
+		if part != expected[i] {
+			t.Errorf("part %d is %+v, expected %+v", i, part, expected[i])
+		}
+	}
+}
+
+func TestMySQLAlterTable(t *testing.T) {
+	cases := []struct {
+		name     string
+		old, new string
+		expected []string
+	}{
+		{
+			name:     "table options only",
+			old:      ") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+			new:      ") ENGINE=InnoDB AUTO_INCREMENT=5 DEFAULT CHARSET=utf8mb4",
+			expected: nil,
+		},
+		{
+			name:     "add column",
+			old:      "  `title` varchar(100) NOT NULL,\n",
+			new:      "  `title` varchar(100) NOT NULL,\n  `body` text,\n",
+			expected: []string{"ALTER TABLE `posts` ADD COLUMN `body` text"},
+		},
+		{
+			name:     "drop column",
+			old:      "  `user_id` int NOT NULL,\n",
+			new:      "",
+			expected: []string{"ALTER TABLE `posts` DROP COLUMN `user_id`"},
+		},
+		{
+			name:     "modify column",
+			old:      "`title` varchar(100) NOT NULL",
+			new:      "`title` varchar(200) NOT NULL",
+			expected: []string{"ALTER TABLE `posts` MODIFY COLUMN `title` varchar(200) NOT NULL"},
+		},
+		{
+			name:     "add index",
+			old:      "  KEY `idx_posts_title` (`title`),\n",
+			new:      "  UNIQUE KEY `idx_posts_user_title` (`user_id`,`title`),\n  KEY `idx_posts_title` (`title`),\n",
+			expected: []string{"ALTER TABLE `posts` ADD UNIQUE KEY `idx_posts_user_title` (`user_id`,`title`)"},
+		},
+		{
+			name:     "drop index",
+			old:      "  KEY `idx_posts_title` (`title`),\n",
+			new:      "",
+			expected: []string{"ALTER TABLE `posts` DROP INDEX `idx_posts_title`"},
+		},
+		{
+			name: "modify index",
+			old:  "KEY `idx_posts_title` (`title`)",
+			new:  "KEY `idx_posts_title` (`title`,`id`)",
+			expected: []string{
+				"ALTER TABLE `posts` DROP INDEX `idx_posts_title`",
+				"ALTER TABLE `posts` ADD KEY `idx_posts_title` (`title`,`id`)",
+			},
+		},
+		{
+			name: "add foreign key",
+			old:  "  `title` varchar(100) NOT NULL,\n",
+			new: "  `title` varchar(100) NOT NULL,\n  `editor_id` int DEFAULT NULL,\n" +
+				"  CONSTRAINT `fk_posts_editors` FOREIGN KEY (`editor_id`) REFERENCES `users` (`id`),\n",
+			expected: []string{
+				"ALTER TABLE `posts` ADD COLUMN `editor_id` int DEFAULT NULL",
+				"ALTER TABLE `posts` ADD CONSTRAINT `fk_posts_editors` FOREIGN KEY (`editor_id`) REFERENCES `users` (`id`)",
+			},
+		},
+		{
+			name:     "drop foreign key",
+			old:      "  KEY `idx_posts_title` (`title`),\n  CONSTRAINT `fk_posts_users` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)\n",
+			new:      "  KEY `idx_posts_title` (`title`)\n",
+			expected: []string{"ALTER TABLE `posts` DROP FOREIGN KEY `fk_posts_users`"},
+		},
+		{
+			name: "modify foreign key",
+			old:  "REFERENCES `users` (`id`)",
+			new:  "REFERENCES `users` (`id`) ON DELETE CASCADE",
+			expected: []string{
+				"ALTER TABLE `posts` DROP FOREIGN KEY `fk_posts_users`",
+				"ALTER TABLE `posts` ADD CONSTRAINT `fk_posts_users` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`) ON DELETE CASCADE",
+			},
+		},
+	}
+
+	ddl := &mySQLDDL{}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			from := baseliner.Object{Type: baseliner.ObjectTypeTable, Name: "posts", SQL: mySQLPostsTable}
+			to := baseliner.Object{Type: baseliner.ObjectTypeTable, Name: "posts", SQL: strings.Replace(mySQLPostsTable, c.old, c.new, 1)}
+			if to.SQL == from.SQL && c.expected != nil {
+				t.Fatalf("fixture change %q is not found", c.old)
+			}
+
+			statements, recreate, err := ddl.alterTable(from, to, nil, nil)
+			if err != nil {
+				t.Fatalf("cannot alter table, error: %v", err)
+			}
+
+			if recreate {
+				t.Errorf("table is recreated, expected ALTER TABLE statements")
+			}
+
+			if strings.Join(statements, "\n") != strings.Join(c.expected, "\n") {
+				t.Errorf("statements are\n%s\nexpected\n%s", strings.Join(statements, "\n"), strings.Join(c.expected, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,168 @@
+// Package schemadiff generates the DDL turning the schema of one database into the schema of another
+package schemadiff
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/olbrichattila/godbmigrator/internal/baseliner"
+	"github.com/olbrichattila/godbmigrator/internal/dbtypemanager"
+)
+
+// ExcludeFunc tells if an object, like the migration tables, is left out of the diff
+type ExcludeFunc func(object baseliner.Object) bool
+
+// objectTableRegex finds the table an index or trigger is defined on
+var objectTableRegex = regexp.MustCompile("(?is)\\bON\\s+[`\"\\[]?([\\w.]+)[`\"\\]]?")
+
+// dialectDDL writes the dialect specific statements of the diff
+type dialectDDL interface {
+	createObject(object baseliner.Object) string
+	dropObject(object baseliner.Object) string
+	// alterTable returns the statements changing a table, rebuilt is true when the table was recreated
+	alterTable(from, to baseliner.Object, fromSchema, toSchema baseliner.Baseliner) (statements []string, rebuilt bool, err error)
+	exclude(object baseliner.Object) bool
+}
+
+func dialectByName(dialect string) (dialectDDL, error) {
+	switch dialect {
+	case dbtypemanager.DbTypeSqlite:
+		return &sqliteDDL{}, nil
+	case dbtypemanager.DbTypeMySQL:
+		return &mySQLDDL{}, nil
+	default:
+		return nil, fmt.Errorf("migration generation is not supported for %s database type", dialect)
+	}
+}
+
+type objectSet struct {
+	objects []baseliner.Object
+	byKey   map[string]baseliner.Object
+}
+
+// Diff returns the statements which turn the schema of from into the schema of to
+// Objects no longer needed are dropped first, then tables are created, altered or dropped, then new objects are created
+func Diff(dialect string, from, to baseliner.Baseliner, exclude ExcludeFunc) ([]string, error) {
+	ddl, err := dialectByName(dialect)
+	if err != nil {
+		return nil, err
+	}
+
+	fromSet, err := loadObjects(from, ddl, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	toSet, err := loadObjects(to, ddl, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var tableStatements []string
+	rebuiltTables := make(map[string]bool)
+	for _, object := range fromSet.objects {
+		if object.Type != baseliner.ObjectTypeTable {
+			continue
+		}
+
+		target, ok := toSet.byKey[objectKey(object)]
+		if !ok {
+			tableStatements = append(tableStatements, ddl.dropObject(object))
+			continue
+		}
+
+		if object.SQL == target.SQL {
+			continue
+		}
+
+		statements, rebuilt, err := ddl.alterTable(object, target, from, to)
+		if err != nil {
+			return nil, err
+		}
+
+		tableStatements = append(tableStatements, statements...)
+		if rebuilt {
+			rebuiltTables[object.Name] = true
+		}
+	}
+
+	for _, object := range toSet.objects {
+		if _, ok := fromSet.byKey[objectKey(object)]; !ok && object.Type == baseliner.ObjectTypeTable {
+			tableStatements = append(tableStatements, ddl.createObject(object))
+		}
+	}
+
+	// A rebuilt table loses its indexes and triggers, views are recreated as they cannot refer to the dropped table
+	recreate := func(object baseliner.Object) bool {
+		if len(rebuiltTables) == 0 {
+			return false
+		}
+
+		if object.Type == baseliner.ObjectTypeView {
+			return true
+		}
+
+		return rebuiltTables[objectTable(object)]
+	}
+
+	var statements []string
+	for i := len(fromSet.objects) - 1; i >= 0; i-- {
+		object := fromSet.objects[i]
+		if object.Type == baseliner.ObjectTypeTable {
+			continue
+		}
+
+		target, ok := toSet.byKey[objectKey(object)]
+		if !ok || target.SQL != object.SQL || (object.Type == baseliner.ObjectTypeView && recreate(object)) {
+			statements = append(statements, ddl.dropObject(object))
+		}
+	}
+
+	statements = append(statements, tableStatements...)
+
+	for _, object := range toSet.objects {
+		if object.Type == baseliner.ObjectTypeTable {
+			continue
+		}
+
+		source, ok := fromSet.byKey[objectKey(object)]
+		if !ok || source.SQL != object.SQL || recreate(object) {
+			statements = append(statements, ddl.createObject(object))
+		}
+	}
+
+	return statements, nil
+}
+
+func loadObjects(schema baseliner.Baseliner, ddl dialectDDL, exclude ExcludeFunc) (objectSet, error) {
+	objects, err := schema.Objects()
+	if err != nil {
+		return objectSet{}, err
+	}
+
+	set := objectSet{byKey: make(map[string]baseliner.Object, len(objects))}
+	for _, object := range objects {
+		if ddl.exclude(object) || (exclude != nil && exclude(object)) {
+			continue
+		}
+
+		set.objects = append(set.objects, object)
+		set.byKey[objectKey(object)] = object
+	}
+
+	return set, nil
+}
+
+func objectKey(object baseliner.Object) string {
+	return object.Type + " " + object.Name
+}
+
+// objectTable returns the table of an index or trigger
+func objectTable(object baseliner.Object) string {
+	matches := objectTableRegex.FindStringSubmatch(object.SQL)
+	if len(matches) < 2 {
+		return ""
+	}
+
+	return matches[1]
+}
@@ -0,0 +1,75 @@
+package schemadiff
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/olbrichattila/godbmigrator/internal/baseliner"
+)
+
+// rebuildTablePrefix names the temporary table of a table rebuild
+const rebuildTablePrefix = "_migrator_new_"
+
+var createTableNameRegex = regexp.MustCompile("(?is)^(\\s*CREATE\\s+TABLE\\s+(?:IF\\s+NOT\\s+EXISTS\\s+)?)([`\"\\[]?[\\w.]+[`\"\\]]?)")
+
+type sqliteDDL struct{}
+
+func (*sqliteDDL) createObject(object baseliner.Object) string {
+	return object.SQL
+}
+
+func (s *sqliteDDL) dropObject(object baseliner.Object) string {
+	return fmt.Sprintf("DROP %s %s", strings.ToUpper(object.Type), s.quote(object.Name))
+}
+
+// alterTable rebuilds the table, as SQLite cannot change columns or constraints of an existing table
+// The columns both versions have are copied into the new table
+func (s *sqliteDDL) alterTable(from, to baseliner.Object, fromSchema, toSchema baseliner.Baseliner) ([]string, bool, error) {
+	fromColumns, err := fromSchema.Columns(from.Name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	toColumns, err := toSchema.Columns(to.Name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var commonColumns []string
+	for _, toColumn := range toColumns {
+		for _, fromColumn := range fromColumns {
+			if strings.EqualFold(fromColumn.Name, toColumn.Name) {
+				commonColumns = append(commonColumns, s.quote(toColumn.Name))
+				break
+			}
+		}
+	}
+
+	newTableName := s.quote(rebuildTablePrefix + to.Name)
+	statements := []string{createTableNameRegex.ReplaceAllString(to.SQL, "${1}"+newTableName)}
+	if len(commonColumns) > 0 {
+		columnList := strings.Join(commonColumns, ", ")
+		statements = append(
+			statements,
+			fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s", newTableName, columnList, columnList, s.quote(from.Name)),
+		)
+	}
+
+	statements = append(
+		statements,
+		fmt.Sprintf("DROP TABLE %s", s.quote(from.Name)),
+		fmt.Sprintf("ALTER TABLE %s RENAME TO %s", newTableName, s.quote(to.Name)),
+	)
+
+	return statements, true, nil
+}
+
+// exclude leaves out the internal tables and the indexes SQLite creates for constraints
+func (*sqliteDDL) exclude(object baseliner.Object) bool {
+	return strings.HasPrefix(object.Name, "sqlite_")
+}
+
+func (*sqliteDDL) quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
@@ -0,0 +1,157 @@
+// Package schemadoc writes Markdown documentation of a schema
+package schemadoc
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/olbrichattila/godbmigrator/internal/baseliner"
+)
+
+// Migration is an applied migration file and its content
+type Migration struct {
+	FileName string
+	SQL      string
+}
+
+// Write writes the tables, views and routines of the schema, every object is annotated with
+// the last of the migrations, in applied order, which mentions its name
+func Write(w io.Writer, schema *baseliner.Schema, migrations []Migration) error {
+	var doc strings.Builder
+	doc.WriteString("# Database Schema\n")
+
+	if len(schema.Tables) > 0 {
+		doc.WriteString("\n## Tables\n")
+	}
+	for _, table := range schema.Tables {
+		writeHeader(&doc, table.Name, table.Name, table.Comment, migrations)
+		writeTable(&doc, table)
+	}
+
+	if len(schema.Views) > 0 {
+		doc.WriteString("\n## Views\n")
+	}
+	for _, view := range schema.Views {
+		title := view.Name
+		if view.Materialized {
+			title += " (materialized)"
+		}
+
+		writeHeader(&doc, title, view.Name, "", migrations)
+		writeSQL(&doc, view.SQL)
+	}
+
+	if len(schema.Routines) > 0 {
+		doc.WriteString("\n## Routines\n")
+	}
+	for _, routine := range schema.Routines {
+		writeHeader(&doc, routine.Name+" ("+routine.Type+")", routine.Name, "", migrations)
+		writeSQL(&doc, routine.SQL)
+	}
+
+	_, err := io.WriteString(w, doc.String())
+	if err != nil {
+		return fmt.Errorf("cannot write schema documentation, error: %v", err)
+	}
+
+	return nil
+}
+
+// writeHeader writes the heading of an object, its comment and the migration which last touched it
+func writeHeader(doc *strings.Builder, title, name, comment string, migrations []Migration) {
+	doc.WriteString("\n### " + title + "\n\n")
+	if comment != "" {
+		doc.WriteString(comment + "\n\n")
+	}
+
+	if fileName := lastMigration(name, migrations); fileName != "" {
+		doc.WriteString("Last changed by `" + fileName + "`\n")
+		return
+	}
+
+	doc.WriteString("Not changed by any applied migration\n")
+}
+
+func writeTable(doc *strings.Builder, table baseliner.Table) {
+	doc.WriteString("\n| Column | Type | Nullable | Default | Comment |\n")
+	doc.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, column := range table.Columns {
+		name := column.Name
+		if column.PrimaryKey {
+			name += " (PK)"
+		}
+
+		nullable := "YES"
+		if column.NotNull || column.PrimaryKey {
+			nullable = "NO"
+		}
+
+		writeRow(doc, name, column.Type, nullable, column.Default.String, column.Comment)
+	}
+
+	if len(table.Constraints) > 0 {
+		doc.WriteString("\n| Constraint | Type | Columns | Definition |\n")
+		doc.WriteString("| --- | --- | --- | --- |\n")
+	}
+	for _, constraint := range table.Constraints {
+		writeRow(doc, constraint.Name, constraint.Type, strings.Join(constraint.Columns, ", "), definition(constraint))
+	}
+
+	if len(table.Indexes) > 0 {
+		doc.WriteString("\n| Index | Unique | Columns |\n")
+		doc.WriteString("| --- | --- | --- |\n")
+	}
+	for _, index := range table.Indexes {
+		unique := "NO"
+		if index.Unique {
+			unique = "YES"
+		}
+
+		writeRow(doc, index.Name, unique, strings.Join(index.Columns, ", "))
+	}
+}
+
+// definition returns the referenced columns of a foreign key and the clause of a check
+func definition(constraint baseliner.Constraint) string {
+	switch constraint.Type {
+	case baseliner.ConstraintForeignKey:
+		return "REFERENCES " + constraint.ReferencedTable + " (" + strings.Join(constraint.ReferencedColumns, ", ") + ")"
+	case baseliner.ConstraintCheck:
+		return constraint.Check
+	}
+
+	return ""
+}
+
+// writeRow writes a table row, escaping the pipes and line breaks of the cells
+func writeRow(doc *strings.Builder, cells ...string) {
+	doc.WriteString("|")
+	for _, cell := range cells {
+		cell = strings.ReplaceAll(cell, "|", "\\|")
+		cell = strings.ReplaceAll(strings.TrimSpace(cell), "\n", "<br>")
+		doc.WriteString(" " + cell + " |")
+	}
+	doc.WriteString("\n")
+}
+
+func writeSQL(doc *strings.Builder, sql string) {
+	doc.WriteString("\n```sql\n" + strings.TrimSpace(sql) + "\n```\n")
+}
+
+// lastMigration returns the last migration mentioning the name as a whole word, the schema of a name is ignored
+func lastMigration(name string, migrations []Migration) string {
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+
+	pattern := regexp.MustCompile(`(?i)(^|[^\w$])` + regexp.QuoteMeta(name) + `([^\w$]|$)`)
+	for i := len(migrations) - 1; i >= 0; i-- {
+		if pattern.MatchString(migrations[i].SQL) {
+			return migrations[i].FileName
+		}
+	}
+
+	return ""
+}
@@ -3,45 +3,302 @@ package migrator
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/olbrichattila/godbmigrator/config"
 	"github.com/olbrichattila/godbmigrator/internal/baseliner"
+	"github.com/olbrichattila/godbmigrator/internal/dbtypemanager"
+	"github.com/olbrichattila/godbmigrator/internal/diagram"
+	"github.com/olbrichattila/godbmigrator/internal/locker"
 	"github.com/olbrichattila/godbmigrator/internal/messager"
 	"github.com/olbrichattila/godbmigrator/internal/migrate"
 	"github.com/olbrichattila/godbmigrator/internal/migrationfile"
+	"github.com/olbrichattila/godbmigrator/internal/schemadiff"
+	"github.com/olbrichattila/godbmigrator/internal/schemadoc"
 )
 
-func New(
-	db *sql.DB,
-	migrationFilePath,
-	tablePrefix string,
-) DBMigrator {
-	return &dbmigrate{
+// ErrLockTimeout is returned when the migration lock could not be acquired within WithLockTimeout
+var ErrLockTimeout = locker.ErrTimeout
+
+// ErrDatabaseNotEmpty is returned by LoadBaseline when the database already has objects, see WithForceBaselineLoad
+var ErrDatabaseNotEmpty = baseliner.ErrDatabaseNotEmpty
+
+// BaselineObjectError is returned by the baseline operations, it tells the type and name of the object they failed on
+type BaselineObjectError = baseliner.ObjectError
+
+// TranslationIssue is a construct SaveTranslatedBaseline could not translate, or copied unchanged so it needs a review
+type TranslationIssue = baseliner.TranslationIssue
+
+// ErrNoSchemaChanges is returned by GenerateMigration when the two schemas are the same
+var ErrNoSchemaChanges = errors.New("no schema changes")
+
+// New creates a migrator, options are validated and the first invalid one is returned as error
+func New(db *sql.DB, opts ...Option) (DBMigrator, error) {
+	if db == nil {
+		return nil, errors.New("database connection cannot be nil")
+	}
+
+	d := &dbmigrate{
 		db:                db,
-		migrationFilePath: migrationFilePath,
-		tablePrefix:       tablePrefix,
+		migrationFilePath: defaultMigrationFilePath,
+		clock:             time.Now,
 		messDispatch:      messager.New(),
 	}
+
+	for _, opt := range opts {
+		if err := opt(d); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := d.init(); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// NewWithPath creates a migrator with the migration path and table prefix, a thin wrapper of New
+//
+// Deprecated: use New with WithMigrationPath and WithTablePrefix
+func NewWithPath(db *sql.DB, migrationFilePath, tablePrefix string) (DBMigrator, error) {
+	return New(db, WithMigrationPath(migrationFilePath), WithTablePrefix(tablePrefix))
+}
+
+// Schema drift kinds returned by DetectDrift
+const (
+	DriftAdded   = baseliner.DriftAdded
+	DriftRemoved = baseliner.DriftRemoved
+	DriftChanged = baseliner.DriftChanged
+)
+
+// Object types of baseline objects, matched by ObjectFilter and returned by DetectDrift
+const (
+	ObjectTypeTable        = baseliner.ObjectTypeTable
+	ObjectTypeView         = baseliner.ObjectTypeView
+	ObjectTypeMaterialView = baseliner.ObjectTypeMaterialView
+	ObjectTypeIndex        = baseliner.ObjectTypeIndex
+	ObjectTypeConstraint   = baseliner.ObjectTypeConstraint
+	ObjectTypeFunction     = baseliner.ObjectTypeFunction
+	ObjectTypeProcedure    = baseliner.ObjectTypeProcedure
+	ObjectTypeTrigger      = baseliner.ObjectTypeTrigger
+	ObjectTypeSequence     = baseliner.ObjectTypeSequence
+	ObjectTypeType         = baseliner.ObjectTypeType
+	ObjectTypeDomain       = baseliner.ObjectTypeDomain
+	ObjectTypeExtension    = baseliner.ObjectTypeExtension
+)
+
+// Schema model returned by Inspect
+type (
+	Schema     = baseliner.Schema
+	Table      = baseliner.Table
+	Column     = baseliner.Column
+	Constraint = baseliner.Constraint
+	Index      = baseliner.Index
+	View       = baseliner.View
+	Routine    = baseliner.Routine
+	Trigger    = baseliner.Trigger
+)
+
+// Diagram formats of GenerateERDiagram
+const (
+	DiagramMermaid = diagram.FormatMermaid
+	DiagramDOT     = diagram.FormatDOT
+)
+
+// Constraint types of the schema model
+const (
+	ConstraintPrimaryKey = baseliner.ConstraintPrimaryKey
+	ConstraintUnique     = baseliner.ConstraintUnique
+	ConstraintForeignKey = baseliner.ConstraintForeignKey
+	ConstraintCheck      = baseliner.ConstraintCheck
+	ConstraintExclude    = baseliner.ConstraintExclude
+)
+
+// ObjectFilter matches baseline objects by name, with a path.Match Pattern or a Regexp
+// An empty ObjectType matches objects of every type, names of constraints and triggers are <table>.<name>
+type ObjectFilter struct {
+	ObjectType string
+	Pattern    string
+	Regexp     *regexp.Regexp
+}
+
+func (f ObjectFilter) matches(objectType, name string) bool {
+	if f.ObjectType != "" && f.ObjectType != objectType {
+		return false
+	}
+
+	if f.Regexp != nil && f.Regexp.MatchString(name) {
+		return true
+	}
+
+	matched, _ := path.Match(f.Pattern, name)
+	return f.Pattern != "" && matched
+}
+
+// BaselineStatement is a statement LoadBaseline executes, and the object it creates
+type BaselineStatement struct {
+	ObjectType string
+	Name       string
+	SQL        string
+}
+
+// SchemaDrift is a table, view, index, routine or trigger which differs from the snapshot
+// Expected is the SQL of the snapshot, Actual is the SQL of the live database
+type SchemaDrift struct {
+	Kind       string
+	ObjectType string
+	Name       string
+	Expected   string
+	Actual     string
+}
+
+// Migration statuses returned by Status
+const (
+	StatusApplied       = migrate.StatusApplied
+	StatusPending       = migrate.StatusPending
+	StatusNotApplicable = migrate.StatusNotApplicable
+)
+
+// Scope is the active environment and tag set
+// Migrations declaring environments or tags, by annotation or env.<name> / tag.<name> sub folder, run only when they match
+type Scope struct {
+	Environment string
+	Tags        []string
 }
 
-// DBMigrator encapsulates migrator functions
+// MigrationStatus is the state of a migration file in the scope
+type MigrationStatus struct {
+	Source   string
+	FileName string
+	Status   string
+}
+
+// DBMigrator encapsulates migrator functions, the optional scope of Migrate, Rollback, Refresh and Status selects environment and tags
 type DBMigrator interface {
 	SubscribeToMessages(callback messager.CallbackFunc)
-	Rollback(count int) error
-	Refresh() error
-	Migrate(count int) error
+	Rollback(count int, scope ...Scope) error
+	Refresh(scope ...Scope) error
+	Migrate(count int, scope ...Scope) error
+	Status(scope ...Scope) ([]MigrationStatus, error)
 	Report() (string, error)
 	AddNewMigrationFiles(customText string) error
+	GenerateMigration(sourceDB, targetDB *sql.DB, name string) error
 	ChecksumValidation() []string
 	SaveBaseline(files ...string) error
 	LoadBaseline(files ...string) error
+	RollbackBaseline(files ...string) error
+	SaveTranslatedBaseline(dialect string, files ...string) ([]TranslationIssue, error)
+	DryRunBaseline(files ...string) ([]BaselineStatement, error)
+	Squash(upTo string) error
+	DetectDrift() ([]SchemaDrift, error)
+	Inspect() (*Schema, error)
+	GenerateERDiagram(w io.Writer, format string, tables ...ObjectFilter) error
+	GenerateSchemaDocs(w io.Writer) error
+	Baseline(at string) error
+	Seed(scope ...Scope) error
+	SeedFresh(scope ...Scope) error
 }
 
 type dbmigrate struct {
 	db                *sql.DB
 	migrationFilePath string
 	tablePrefix       string
+	fsys              fs.FS
+	isCustomFS        bool
+	sources           []migrationfile.Source
+	seedFS            fs.FS
+	seedFilePath      string
+	schemaSnapshot    string
+	baselineData      []string
+	splitBaseline     bool
+	forceBaseline     bool
+	baselineInclude   []ObjectFilter
+	baselineExclude   []ObjectFilter
+	dialect           string
+	logger            *slog.Logger
+	clock             func() time.Time
+	lockTimeout       time.Duration
 	messDispatch      messager.Messager
+	postgresOptions   PostgresOptions
+}
+
+func (d *dbmigrate) init() error {
+	if d.dialect == "" {
+		dialect, err := dbtypemanager.GetDiverType(d.db)
+		if err != nil {
+			return fmt.Errorf("%w, use WithDialect to set it explicitly", err)
+		}
+		d.dialect = dialect
+	}
+
+	if d.lockTimeout > 0 && !locker.Supported(d.dialect) {
+		return fmt.Errorf("lock timeout is not supported for %s database type", d.dialect)
+	}
+
+	if d.dialect != DialectPostgres && d.hasPostgresOptions() {
+		return fmt.Errorf("postgres options are not supported for %s database type", d.dialect)
+	}
+
+	if d.fsys == nil {
+		d.fsys = os.DirFS(d.migrationFilePath)
+	} else {
+		fsys, err := fs.Sub(d.fsys, path.Clean(d.migrationFilePath))
+		if err != nil {
+			return fmt.Errorf("invalid migration path %s for the file system, error: %w", d.migrationFilePath, err)
+		}
+		d.fsys = fsys
+		d.isCustomFS = true
+	}
+
+	if d.seedFS == nil {
+		seedFS, err := fs.Sub(d.fsys, migrationfile.SeedFolder)
+		if err != nil {
+			return fmt.Errorf("invalid seed folder, error: %w", err)
+		}
+		d.seedFS = seedFS
+	}
+
+	if d.logger != nil {
+		logger := d.logger
+		d.messDispatch.Register(func(eventType int, message string) {
+			logger.Info("migrator", "event", eventType, "message", message)
+		})
+	}
+
+	return nil
+}
+
+func (d *dbmigrate) addSource(name string, fsys fs.FS) error {
+	if name == "" {
+		return errors.New("migration source name cannot be empty")
+	}
+
+	for _, source := range d.sources {
+		if source.Name == name {
+			return fmt.Errorf("migration source %s is already registered", name)
+		}
+	}
+
+	d.sources = append(d.sources, migrationfile.Source{Name: name, FS: fsys})
+
+	return nil
+}
+
+func (d *dbmigrate) hasPostgresOptions() bool {
+	return d.postgresOptions.TrackingSchema != "" ||
+		len(d.postgresOptions.SearchPath) > 0 ||
+		len(d.postgresOptions.BaselineSchemas) > 0
 }
 
 // SubscribeToMessages receive messages from the migrator, events happening
@@ -52,40 +309,93 @@ func (d *dbmigrate) SubscribeToMessages(callback messager.CallbackFunc) {
 // Rollback rolls back last migrated items or all if count is 0
 func (d *dbmigrate) Rollback(
 	count int,
+	scope ...Scope,
 ) error {
 	m, provider, err := d.getMigrator()
 	if err != nil {
 		return err
 	}
 
-	return m.Rollback(provider, d.migrationFilePath, count, false)
+	return d.withLock(func() error {
+		err = m.Rollback(provider, count, false, activeScope(scope))
+		if err != nil {
+			return err
+		}
+
+		return d.saveSchemaSnapshot()
+	})
 }
 
 // Refresh runs a full rollback and migrate again
-func (d *dbmigrate) Refresh() error {
+func (d *dbmigrate) Refresh(scope ...Scope) error {
 	m, provider, err := d.getMigrator()
 	if err != nil {
 		return err
 	}
 
-	err = m.Rollback(provider, d.migrationFilePath, 0, true)
-	if err != nil {
-		return err
-	}
+	return d.withLock(func() error {
+		err = d.syncBaseline(m, provider)
+		if err != nil {
+			return err
+		}
+
+		err = m.Rollback(provider, 0, true, activeScope(scope))
+		if err != nil {
+			return err
+		}
+
+		err = m.Migrate(provider, 0, activeScope(scope))
+		if err != nil {
+			return err
+		}
 
-	return m.Migrate(provider, d.migrationFilePath, 0)
+		return d.saveSchemaSnapshot()
+	})
 }
 
 // Migrate execute migrations
 func (d *dbmigrate) Migrate(
 	count int,
+	scope ...Scope,
 ) error {
 	m, provider, err := d.getMigrator()
 	if err != nil {
 		return err
 	}
 
-	return m.Migrate(provider, d.migrationFilePath, count)
+	return d.withLock(func() error {
+		err = d.syncBaseline(m, provider)
+		if err != nil {
+			return err
+		}
+
+		err = m.Migrate(provider, count, activeScope(scope))
+		if err != nil {
+			return err
+		}
+
+		return d.saveSchemaSnapshot()
+	})
+}
+
+// Status lists every migration file as applied, pending or not applicable in the scope
+func (d *dbmigrate) Status(scope ...Scope) ([]MigrationStatus, error) {
+	m, provider, err := d.getMigrator()
+	if err != nil {
+		return nil, err
+	}
+
+	fileStatuses, err := m.Status(provider, activeScope(scope))
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, len(fileStatuses))
+	for i, fileStatus := range fileStatuses {
+		statuses[i] = MigrationStatus(fileStatus)
+	}
+
+	return statuses, nil
 }
 
 // Report return a report of the already executed migrations
@@ -95,12 +405,16 @@ func (d *dbmigrate) Report() (string, error) {
 		return "", err
 	}
 
-	return m.Report(provider, d.migrationFilePath)
+	return m.Report(provider)
 }
 
 // AddNewMigrationFiles adds a new blank migration file and a rollback file
 func (d *dbmigrate) AddNewMigrationFiles(customText string) error {
-	mf := migrationfile.New(d.migrationFilePath)
+	if d.isCustomFS {
+		return errors.New("cannot create migration files when the migrations are read from a custom file system")
+	}
+
+	mf := d.getMigrationFileManager()
 	files, err := mf.CreateNewMigrationFiles(d.migrationFilePath, customText)
 	if err != nil {
 		return err
@@ -113,6 +427,135 @@ func (d *dbmigrate) AddNewMigrationFiles(customText string) error {
 	return nil
 }
 
+// GenerateMigration writes a migration turning the schema of sourceDB into the schema of targetDB, and its rollback
+// Both databases have to be SQLite or MySQL, the migration tables are left out
+func (d *dbmigrate) GenerateMigration(sourceDB, targetDB *sql.DB, name string) error {
+	if d.isCustomFS {
+		return errors.New("cannot create migration files when the migrations are read from a custom file system")
+	}
+
+	sourceDialect, err := d.databaseDialect(sourceDB)
+	if err != nil {
+		return err
+	}
+
+	targetDialect, err := d.databaseDialect(targetDB)
+	if err != nil {
+		return err
+	}
+
+	if sourceDialect != targetDialect {
+		return fmt.Errorf("cannot generate migration between %s and %s databases", sourceDialect, targetDialect)
+	}
+
+	source := baseliner.New(sourceDB, sourceDialect, nil, nil)
+	target := baseliner.New(targetDB, targetDialect, nil, nil)
+
+	migration, err := schemadiff.Diff(sourceDialect, source, target, d.isTrackingObject)
+	if err != nil {
+		return err
+	}
+
+	if len(migration) == 0 {
+		return ErrNoSchemaChanges
+	}
+
+	rollback, err := schemadiff.Diff(sourceDialect, target, source, d.isTrackingObject)
+	if err != nil {
+		return err
+	}
+
+	files, err := d.getMigrationFileManager().CreateMigrationFilesWithContent(
+		d.migrationFilePath,
+		name,
+		joinStatements(migration),
+		joinStatements(rollback),
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, fileName := range files {
+		d.messDispatch.Dispatch(config.MigrationFileCreated, fileName)
+	}
+
+	return nil
+}
+
+// databaseDialect detects the dialect of a database, the configured dialect is used only for the connection of the migrator
+// The dialect of another connection with an unknown driver cannot be told, so it is an error
+func (d *dbmigrate) databaseDialect(db *sql.DB) (string, error) {
+	if db == nil {
+		return "", errors.New("database connection cannot be nil")
+	}
+
+	if db == d.db {
+		return d.dialect, nil
+	}
+
+	dialect, err := dbtypemanager.GetDiverType(db)
+	if err != nil {
+		return "", fmt.Errorf("cannot detect the database type of the connection, error: %v", err)
+	}
+
+	return dialect, nil
+}
+
+// isTrackingObject tells if the object is one of the tables the migrator keeps its state in
+func (d *dbmigrate) isTrackingObject(object baseliner.Object) bool {
+	tablePrefix := d.tablePrefix
+	if tablePrefix == "" {
+		tablePrefix = migrate.DefaultTablePrefix
+	}
+
+	for _, suffix := range []string{"_migrations", "_migration_reports", "_seeds", "_seed_reports"} {
+		tableName := tablePrefix + suffix
+		if object.Type == baseliner.ObjectTypeTable && (object.Name == tableName || strings.HasSuffix(object.Name, "."+tableName)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newBaseliner creates the baseliner of the migrator database with its filters and PostgreSQL baseline schemas
+func (d *dbmigrate) newBaseliner() baseliner.Baseliner {
+	return baseliner.New(d.db, d.dialect, d.messDispatch, d.isBaselineObject, d.postgresOptions.BaselineSchemas...)
+}
+
+// isBaselineObject applies the include and exclude filters, tracking tables are left out unless an include filter matches them
+func (d *dbmigrate) isBaselineObject(objectType, name string) bool {
+	included := matchesObjectFilters(d.baselineInclude, objectType, name)
+	if len(d.baselineInclude) > 0 && !included {
+		return false
+	}
+
+	if matchesObjectFilters(d.baselineExclude, objectType, name) {
+		return false
+	}
+
+	return included || !d.isTrackingObject(baseliner.Object{Type: objectType, Name: name})
+}
+
+func matchesObjectFilters(filters []ObjectFilter, objectType, name string) bool {
+	for _, filter := range filters {
+		if filter.matches(objectType, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinStatements(statements []string) string {
+	var builder strings.Builder
+	for _, statement := range statements {
+		builder.WriteString(strings.TrimSuffix(strings.TrimSpace(statement), ";") + ";\n\n")
+	}
+
+	return builder.String()
+}
+
 // ChecksumValidation validates if the checksums are correct and nothing changed
 func (d *dbmigrate) ChecksumValidation() []string {
 	m, provider, err := d.getMigrator()
@@ -120,42 +563,410 @@ func (d *dbmigrate) ChecksumValidation() []string {
 		return []string{err.Error()}
 	}
 
-	return m.ChecksumValidation(provider, d.migrationFilePath)
+	return m.ChecksumValidation(provider)
 }
 
 // SaveBaseline will save the current status of your database as baseline, which means the migration can start from this point
+// The baseline is stamped with the newest applied migration, LoadBaseline records it so Migrate starts after it
+// The rows of the tables set by WithBaselineData are saved as inserts, LoadBaseline inserts them after the schema
 func (d *dbmigrate) SaveBaseline(files ...string) error {
-	b := baseliner.New(d.db)
-	if len(files) == 0 {
-		return b.Save(d.migrationFilePath)
+	migrationFilePath := d.migrationFilePath
+	if len(files) > 0 {
+		migrationFilePath = files[0]
+	} else if d.isCustomFS {
+		return errors.New("cannot save baseline into a custom file system, pass the target folder")
 	}
 
-	return b.Save(files[0])
+	version, err := d.appliedVersion()
+	if err != nil {
+		return err
+	}
+
+	b := d.newBaseliner()
+
+	return b.Save(migrationFilePath, d.baselineSaveOptions(version))
+}
+
+// SaveTranslatedBaseline saves the baseline of the database translated into another dialect, with its rollback
+// Column types, auto increments, defaults, quoting, constraints and indexes are translated, views are copied
+// The returned issues list what could not be translated, like routines and triggers, and what needs a review
+// Baseline data and split baselines are not translated, an error is returned when they are set
+func (d *dbmigrate) SaveTranslatedBaseline(dialect string, files ...string) ([]TranslationIssue, error) {
+	if len(d.baselineData) > 0 || d.splitBaseline {
+		return nil, errors.New("cannot save translated baseline with baseline data or split baseline options")
+	}
+
+	migrationFilePath := d.migrationFilePath
+	if len(files) > 0 {
+		migrationFilePath = files[0]
+	} else if d.isCustomFS {
+		return nil, errors.New("cannot save baseline into a custom file system, pass the target folder")
+	}
+
+	version, err := d.appliedVersion()
+	if err != nil {
+		return nil, err
+	}
+
+	b := d.newBaseliner()
+
+	return b.SaveTranslated(migrationFilePath, dialect, baseliner.SaveOptions{Version: version})
+}
+
+func (d *dbmigrate) baselineSaveOptions(version string) baseliner.SaveOptions {
+	return baseliner.SaveOptions{
+		Version:    version,
+		DataTables: d.baselineData,
+		Split:      d.splitBaseline,
+	}
 }
 
 // LoadBaseline loads the backed up baseline schema to the database
+// It is refused with ErrDatabaseNotEmpty when the database already has objects, unless WithForceBaselineLoad is used
+// The load is one transaction on SQLite, PostgreSQL and DuckDB, MySQL commits every DDL statement
+// The version stamped into the baseline is recorded in the same transaction, so Migrate continues after the migrations it covers
 func (d *dbmigrate) LoadBaseline(files ...string) error {
-	b := baseliner.New(d.db)
+	b := d.newBaseliner()
+	fsys := d.baselineFS(files)
+
+	version, err := b.Version(fsys)
+	if err != nil {
+		return err
+	}
+
+	options := baseliner.LoadOptions{Force: d.forceBaseline}
+	if version != "" {
+		_, provider, err := d.getMigrator()
+		if err != nil {
+			return err
+		}
 
-	if len(files) == 0 {
-		return b.Load(d.migrationFilePath)
+		options.Record = func(db baseliner.Execer) error {
+			return provider.AddBaselineMarkerWith(db, version)
+		}
 	}
 
-	return b.Load(files[0])
+	return d.withLock(func() error {
+		return b.Load(fsys, options)
+	})
 }
 
-func (d *dbmigrate) getMigrator() (migrate.Migrator, migrate.MigrationProvider, error) {
-	provider, err := migrate.NewProvider(d.tablePrefix, d.db)
+// RollbackBaseline drops the objects of the baseline with baseline-rollback.sql, saved next to the baseline
+// The baseline version is forgotten, so LoadBaseline can load it again; migrations applied after it have to be rolled back first
+func (d *dbmigrate) RollbackBaseline(files ...string) error {
+	m, provider, err := d.getMigrator()
+	if err != nil {
+		return err
+	}
+
+	b := d.newBaseliner()
+
+	return d.withLock(func() error {
+		return m.RevertBaseline(provider, func() error {
+			return b.Rollback(d.baselineFS(files))
+		})
+	})
+}
+
+// DryRunBaseline returns the statements LoadBaseline would execute, in order, without touching the database
+func (d *dbmigrate) DryRunBaseline(files ...string) ([]BaselineStatement, error) {
+	objects, err := d.newBaseliner().Statements(d.baselineFS(files))
+	if err != nil {
+		return nil, err
+	}
+
+	statements := make([]BaselineStatement, len(objects))
+	for i, object := range objects {
+		statements[i] = BaselineStatement{ObjectType: object.Type, Name: object.Name, SQL: object.SQL}
+	}
+
+	return statements, nil
+}
+
+func (d *dbmigrate) baselineFS(files []string) fs.FS {
+	if len(files) > 0 {
+		return os.DirFS(files[0])
+	}
+
+	return d.fsys
+}
+
+// Squash replaces the migration files up to and including upTo with a baseline of the schema they produced
+// The database has to be migrated exactly to upTo, the squashed files are moved into the squashed sub folder
+// Other databases which applied the squashed files are switched to the baseline by their next Migrate
+func (d *dbmigrate) Squash(upTo string) error {
+	if d.isCustomFS {
+		return errors.New("cannot squash migrations read from a custom file system")
+	}
+
+	m, provider, err := d.getMigrator()
+	if err != nil {
+		return err
+	}
+
+	return d.withLock(func() error {
+		files, err := m.SquashableFiles(provider, upTo)
+		if err != nil {
+			return err
+		}
+
+		b := d.newBaseliner()
+		err = b.Save(d.migrationFilePath, d.baselineSaveOptions(upTo))
+		if err != nil {
+			return err
+		}
+
+		err = d.getMigrationFileManager().ArchiveFiles(d.migrationFilePath, files)
+		if err != nil {
+			return err
+		}
+
+		return m.SyncBaseline(provider, upTo)
+	})
+}
+
+// Baseline adopts the migrator on an existing database, the migrations up to and including at are recorded as applied without running them
+func (d *dbmigrate) Baseline(at string) error {
+	m, provider, err := d.getMigrator()
+	if err != nil {
+		return err
+	}
+
+	return d.withLock(func() error {
+		return m.Baseline(provider, at)
+	})
+}
+
+// appliedVersion returns the newest applied migration, the migration tables are not created if they are missing
+func (d *dbmigrate) appliedVersion() (string, error) {
+	provider, exists, err := migrate.OpenProvider(d.db, d.providerOptions())
+	if err != nil || !exists {
+		return "", err
+	}
+
+	m := migrate.New(d.db, d.getMigrationFileManager(), d.messDispatch, d.postgresOptions.SearchPath)
+
+	return m.AppliedVersion(provider)
+}
+
+// syncBaseline switches a database to the squashed baseline, if the migration folder has a stamped one
+func (d *dbmigrate) syncBaseline(m migrate.Migrator, provider migrate.MigrationProvider) error {
+	version, err := d.newBaseliner().Version(d.fsys)
+	if err != nil || version == "" {
+		return err
+	}
+
+	return m.SyncBaseline(provider, version)
+}
+
+// DetectDrift compares the live database with the schema snapshot, or with the baseline if WithSchemaSnapshot is not used
+func (d *dbmigrate) DetectDrift() ([]SchemaDrift, error) {
+	fsys, fileName := d.fsys, ""
+	if d.schemaSnapshot != "" {
+		fsys, fileName = os.DirFS(filepath.Dir(d.schemaSnapshot)), filepath.Base(d.schemaSnapshot)
+	}
+
+	drifts, err := d.newBaseliner().Drift(fsys, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	schemaDrifts := make([]SchemaDrift, len(drifts))
+	for i, drift := range drifts {
+		schemaDrifts[i] = SchemaDrift(drift)
+	}
+
+	return schemaDrifts, nil
+}
 
+// Inspect returns the typed model of the schema, the baseline filters apply and the tracking tables are left out
+func (d *dbmigrate) Inspect() (*Schema, error) {
+	return d.newBaseliner().Inspect()
+}
+
+// GenerateERDiagram writes a Mermaid erDiagram or a Graphviz DOT graph of the tables, their columns and foreign keys
+// Only the tables matching one of the filters are drawn, every table when there is none
+func (d *dbmigrate) GenerateERDiagram(w io.Writer, format string, tables ...ObjectFilter) error {
+	err := validateObjectFilters(tables)
+	if err != nil {
+		return err
+	}
+
+	schema, err := d.Inspect()
+	if err != nil {
+		return err
+	}
+
+	return diagram.Write(w, format, schema, func(table string) bool {
+		for _, filter := range tables {
+			if filter.matches(ObjectTypeTable, table) {
+				return true
+			}
+		}
+
+		return len(tables) == 0
+	})
+}
+
+// GenerateSchemaDocs writes Markdown documentation of the tables, views and routines
+// Every object is annotated with the last applied migration file which mentions its name
+func (d *dbmigrate) GenerateSchemaDocs(w io.Writer) error {
+	schema, err := d.Inspect()
+	if err != nil {
+		return err
+	}
+
+	// The migration tables are not created, a database without them has no applied migrations
+	provider, exists, err := migrate.OpenProvider(d.db, d.providerOptions())
+	if err != nil {
+		return err
+	}
+
+	fileManager := d.getMigrationFileManager()
+	var statuses []migrate.FileStatus
+	if exists {
+		m := migrate.New(d.db, fileManager, d.messDispatch, d.postgresOptions.SearchPath)
+		statuses, err = m.Status(provider, migrationfile.Scope{})
+		if err != nil {
+			return err
+		}
+	}
+
+	var migrations []schemadoc.Migration
+	for _, status := range statuses {
+		if status.Status != migrate.StatusApplied {
+			continue
+		}
+
+		content, err := fileManager.ReadFile(status.Source, status.FileName)
+		if err != nil {
+			return fmt.Errorf("cannot read migration %s, error: %v", status.FileName, err)
+		}
+
+		migrations = append(migrations, schemadoc.Migration{FileName: status.FileName, SQL: string(content)})
+	}
+
+	return schemadoc.Write(w, schema, migrations)
+}
+
+func (d *dbmigrate) saveSchemaSnapshot() error {
+	if d.schemaSnapshot == "" {
+		return nil
+	}
+
+	return d.newBaseliner().SaveSnapshot(d.schemaSnapshot)
+}
+
+// Seed runs the seeds which were not run yet, and the rerun-on-change seeds whose content changed
+func (d *dbmigrate) Seed(scope ...Scope) error {
+	return d.seed(false, scope)
+}
+
+// SeedFresh runs every applicable seed again, regardless of what was seeded before
+func (d *dbmigrate) SeedFresh(scope ...Scope) error {
+	return d.seed(true, scope)
+}
+
+func (d *dbmigrate) seed(fresh bool, scope []Scope) error {
+	seedProvider, err := migrate.NewSeedProvider(d.db, d.providerOptions())
+	if err != nil {
+		return err
+	}
+
+	m := migrate.New(d.db, d.getMigrationFileManager(), d.messDispatch, d.postgresOptions.SearchPath)
+	sources := []migrationfile.Source{{FS: d.seedFS}}
+	if _, err := fs.Stat(d.seedFS, "."); errors.Is(err, fs.ErrNotExist) {
+		if d.seedFilePath != "" {
+			return fmt.Errorf("seed folder %s does not exist", d.seedFilePath)
+		}
+
+		// The default seeds folder is optional, without it there are no seeds
+		sources = nil
+	}
+
+	seedFileManager := migrationfile.NewSeedManager(d.migrationFilePath, d.clock, sources...)
+
+	return d.withLock(func() error {
+		return m.Seed(seedProvider, seedFileManager, fresh, activeScope(scope))
+	})
+}
+
+// activeScope returns the first scope passed, no scope means no environment and no tags are active
+func activeScope(scope []Scope) migrationfile.Scope {
+	if len(scope) == 0 {
+		return migrationfile.Scope{}
+	}
+
+	return migrationfile.Scope(scope[0])
+}
+
+func (d *dbmigrate) withLock(callback func() error) error {
+	if d.lockTimeout == 0 {
+		return callback()
+	}
+
+	lock, err := locker.New(d.db, d.dialect, d.lockName())
+	if err != nil {
+		return err
+	}
+
+	err = lock.Lock(d.lockTimeout)
+	if err != nil {
+		return err
+	}
+
+	err = callback()
+	if unlockErr := lock.Unlock(); err == nil {
+		err = unlockErr
+	}
+
+	return err
+}
+
+func (d *dbmigrate) lockName() string {
+	tablePrefix := d.tablePrefix
+	if tablePrefix == "" {
+		tablePrefix = migrate.DefaultTablePrefix
+	}
+
+	name := tablePrefix + "_migrations"
+	if d.postgresOptions.TrackingSchema != "" {
+		name = d.postgresOptions.TrackingSchema + "." + name
+	}
+
+	return name
+}
+
+func (d *dbmigrate) getMigrationFileManager() migrationfile.Manager {
+	sources := append([]migrationfile.Source{{FS: d.fsys}}, d.sources...)
+
+	return migrationfile.New(d.migrationFilePath, d.clock, sources...)
+}
+
+func (d *dbmigrate) getMigrator() (migrate.Migrator, migrate.MigrationProvider, error) {
+	provider, err := migrate.NewProvider(d.db, d.providerOptions())
 	if err != nil {
 		return nil, nil, err
 	}
 
 	migrator := migrate.New(
 		d.db,
-		migrationfile.New(d.migrationFilePath),
+		d.getMigrationFileManager(),
 		d.messDispatch,
+		d.postgresOptions.SearchPath,
 	)
 
 	return migrator, provider, nil
 }
+
+func (d *dbmigrate) providerOptions() migrate.ProviderOptions {
+	return migrate.ProviderOptions{
+		Dialect:     d.dialect,
+		TablePrefix: d.tablePrefix,
+		TrackingSchema: migrate.TrackingSchema{
+			Name:          d.postgresOptions.TrackingSchema,
+			CreateMissing: d.postgresOptions.CreateTrackingSchema,
+		},
+		Clock: d.clock,
+	}
+}
@@ -0,0 +1,263 @@
+package migrator
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"regexp"
+	"time"
+
+	"github.com/olbrichattila/godbmigrator/internal/dbtypemanager"
+)
+
+// Supported dialects, can be forced with WithDialect when the driver cannot be detected, for example when it is wrapped
+const (
+	DialectSQLite   = dbtypemanager.DbTypeSqlite
+	DialectPostgres = dbtypemanager.DbTypePostgres
+	DialectMySQL    = dbtypemanager.DbTypeMySQL
+	DialectFirebird = dbtypemanager.DbTypeFirebird
+	DialectDuckDB   = dbtypemanager.DbTypeDuckDB
+)
+
+const defaultMigrationFilePath = "./migrations"
+
+var tablePrefixRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Option configures the migrator, see New
+type Option func(*dbmigrate) error
+
+// WithMigrationPath sets the folder of the migration files, it is relative to the file system root when WithFS is used
+func WithMigrationPath(migrationFilePath string) Option {
+	return func(d *dbmigrate) error {
+		if migrationFilePath == "" {
+			return errors.New("migration path cannot be empty")
+		}
+
+		d.migrationFilePath = migrationFilePath
+		return nil
+	}
+}
+
+// WithTablePrefix sets the prefix of the migration tables, like <prefix>_migrations
+func WithTablePrefix(tablePrefix string) Option {
+	return func(d *dbmigrate) error {
+		if tablePrefix != "" && !tablePrefixRegex.MatchString(tablePrefix) {
+			return fmt.Errorf("invalid table prefix %s, only letters, digits and underscore allowed", tablePrefix)
+		}
+
+		d.tablePrefix = tablePrefix
+		return nil
+	}
+}
+
+// WithFS reads migration and baseline files from fsys, for example an embed.FS
+// New migration files cannot be created and baselines cannot be saved into it
+func WithFS(fsys fs.FS) Option {
+	return func(d *dbmigrate) error {
+		if fsys == nil {
+			return errors.New("file system cannot be nil")
+		}
+
+		d.fsys = fsys
+		return nil
+	}
+}
+
+// WithDialect forces the database dialect instead of detecting it from the driver
+func WithDialect(dialect string) Option {
+	return func(d *dbmigrate) error {
+		switch dialect {
+		case DialectSQLite, DialectPostgres, DialectMySQL, DialectFirebird, DialectDuckDB:
+			d.dialect = dialect
+			return nil
+		default:
+			return fmt.Errorf("unknown dialect %s", dialect)
+		}
+	}
+}
+
+// WithLogger logs every migrator message, in addition to the subscribed callbacks
+func WithLogger(logger *slog.Logger) Option {
+	return func(d *dbmigrate) error {
+		if logger == nil {
+			return errors.New("logger cannot be nil")
+		}
+
+		d.logger = logger
+		return nil
+	}
+}
+
+// WithClock sets the time source used for migration batches, reports and new file names
+func WithClock(clock func() time.Time) Option {
+	return func(d *dbmigrate) error {
+		if clock == nil {
+			return errors.New("clock cannot be nil")
+		}
+
+		d.clock = clock
+		return nil
+	}
+}
+
+// WithLockTimeout makes Migrate, Rollback, Refresh and the baseline loads and rollbacks wait for an advisory lock, so concurrent runs are serialized
+// Supported by PostgreSQL and MySQL, zero means no locking
+func WithLockTimeout(timeout time.Duration) Option {
+	return func(d *dbmigrate) error {
+		if timeout < 0 {
+			return errors.New("lock timeout cannot be negative")
+		}
+
+		d.lockTimeout = timeout
+		return nil
+	}
+}
+
+// PostgresOptions controls which PostgreSQL schemas the migrator works with
+type PostgresOptions struct {
+	// TrackingSchema holds the <prefix>_migrations and <prefix>_migration_reports tables, empty means the search_path
+	TrackingSchema string
+	// CreateTrackingSchema creates the tracking schema if it is missing
+	CreateTrackingSchema bool
+	// SearchPath is set for every migration and rollback statement, empty keeps the connection default
+	SearchPath []string
+	// BaselineSchemas are saved by SaveBaseline with schema qualified DDL, empty means current_schema()
+	BaselineSchemas []string
+}
+
+// WithPostgresOptions sets tracking schema, search_path and baseline schemas, it is the only way to set them
+// New returns an error when they are set for another database type
+func WithPostgresOptions(options PostgresOptions) Option {
+	return func(d *dbmigrate) error {
+		d.postgresOptions = options
+		return nil
+	}
+}
+
+// WithSeedPath sets the folder of the seed files, by default it is the seeds sub folder of the migration path
+func WithSeedPath(seedFilePath string) Option {
+	return func(d *dbmigrate) error {
+		if seedFilePath == "" {
+			return errors.New("seed path cannot be empty")
+		}
+
+		d.seedFS = os.DirFS(seedFilePath)
+		d.seedFilePath = seedFilePath
+		return nil
+	}
+}
+
+// WithSchemaSnapshot saves the schema into snapshotFileName after every successful Migrate, Rollback and Refresh
+// DetectDrift compares the live database with it, instead of the baseline
+func WithSchemaSnapshot(snapshotFileName string) Option {
+	return func(d *dbmigrate) error {
+		if snapshotFileName == "" {
+			return errors.New("schema snapshot file name cannot be empty")
+		}
+
+		d.schemaSnapshot = snapshotFileName
+		return nil
+	}
+}
+
+// WithBaselineData saves the rows of the matching tables into the baseline, like lookup tables of statuses or currencies
+// Patterns are table names or path.Match patterns, like "lookup_*"
+func WithBaselineData(tables ...string) Option {
+	return func(d *dbmigrate) error {
+		for _, table := range tables {
+			if _, err := path.Match(table, ""); err != nil {
+				return fmt.Errorf("invalid baseline data table pattern %s, error: %w", table, err)
+			}
+		}
+
+		d.baselineData = append(d.baselineData, tables...)
+		return nil
+	}
+}
+
+// WithSplitBaseline saves the baseline as one file per object in the baseline folder, with a manifest of the load order
+func WithSplitBaseline() Option {
+	return func(d *dbmigrate) error {
+		d.splitBaseline = true
+		return nil
+	}
+}
+
+// WithForceBaselineLoad lets LoadBaseline load into a database which already has objects
+func WithForceBaselineLoad() Option {
+	return func(d *dbmigrate) error {
+		d.forceBaseline = true
+		return nil
+	}
+}
+
+// WithBaselineInclude keeps only the objects matching one of the filters in baselines, snapshots and drift detection
+func WithBaselineInclude(filters ...ObjectFilter) Option {
+	return func(d *dbmigrate) error {
+		err := validateObjectFilters(filters)
+		if err != nil {
+			return err
+		}
+
+		d.baselineInclude = append(d.baselineInclude, filters...)
+		return nil
+	}
+}
+
+// WithBaselineExclude leaves the objects matching one of the filters out of baselines, snapshots and drift detection
+func WithBaselineExclude(filters ...ObjectFilter) Option {
+	return func(d *dbmigrate) error {
+		err := validateObjectFilters(filters)
+		if err != nil {
+			return err
+		}
+
+		d.baselineExclude = append(d.baselineExclude, filters...)
+		return nil
+	}
+}
+
+func validateObjectFilters(filters []ObjectFilter) error {
+	for _, filter := range filters {
+		if filter.Pattern == "" && filter.Regexp == nil {
+			return errors.New("object filter needs a pattern or a regular expression")
+		}
+
+		if _, err := path.Match(filter.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid object filter pattern %s, error: %w", filter.Pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// WithSource registers an additional named folder of migration files, for example a plugin module
+// Files of all sources are merged into one timeline ordered by file name, the source is recorded for every migration
+func WithSource(name, migrationFilePath string) Option {
+	return func(d *dbmigrate) error {
+		if migrationFilePath == "" {
+			return fmt.Errorf("migration path of source %s cannot be empty", name)
+		}
+
+		return d.addSource(name, os.DirFS(migrationFilePath))
+	}
+}
+
+// WithSourceFS registers an additional named migration source read from a folder of fsys
+func WithSourceFS(name string, fsys fs.FS, migrationFilePath string) Option {
+	return func(d *dbmigrate) error {
+		if fsys == nil {
+			return fmt.Errorf("file system of source %s cannot be nil", name)
+		}
+
+		sourceFS, err := fs.Sub(fsys, path.Clean(migrationFilePath))
+		if err != nil {
+			return fmt.Errorf("invalid migration path %s for source %s, error: %w", migrationFilePath, name, err)
+		}
+
+		return d.addSource(name, sourceFS)
+	}
+}
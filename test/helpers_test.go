@@ -8,6 +8,8 @@ import (
 	"fmt"
 	"io"
 	"os"
+
+	migrator "github.com/olbrichattila/godbmigrator"
 )
 
 func initMemorySqlite() *sql.DB {
@@ -85,6 +87,26 @@ func copyFile(src, dst string) error {
 	return nil
 }
 
+func copyFolder(src, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		err = copyFile(src+"/"+entry.Name(), dst+"/"+entry.Name())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func haveReportRecord(db *sql.DB, fileName, createdAt, status, message string) error {
 	sql := `INSERT INTO %s_migration_reports
 			(file_name, created_at, result_status, message)
@@ -135,3 +157,38 @@ func getFileSize(filename string) (int64, error) {
 
 	return fileInfo.Size(), nil
 }
+
+func initMemoryDuckDB() *sql.DB {
+	db, err := sql.Open("duckdb", "")
+	if err != nil {
+		panic(err)
+	}
+
+	return db
+}
+
+func countInDuckDBCatalog(db *sql.DB, catalogFunction string) (int, error) {
+	query := "SELECT count(*) FROM " + catalogFunction
+
+	var count int
+	err := db.QueryRow(query).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func newTestMigrator(db *sql.DB, migrationFilePath string, opts ...migrator.Option) migrator.DBMigrator {
+	opts = append([]migrator.Option{
+		migrator.WithMigrationPath(migrationFilePath),
+		migrator.WithTablePrefix(tablePrefix),
+	}, opts...)
+
+	m, err := migrator.New(db, opts...)
+	if err != nil {
+		panic(err)
+	}
+
+	return m
+}
@@ -23,7 +23,9 @@ func TestAddTestRunner(t *testing.T) {
 func (suite *AddTestSuite) SetupTest() {
 	resetTestMigrationPath()
 	db := initMemorySqlite()
-	suite.migrator = migrator.New(db, testMigrationFilePath, tablePrefix)
+	var err error
+	suite.migrator, err = migrator.NewWithPath(db, testMigrationFilePath, tablePrefix)
+	suite.Require().NoError(err)
 }
 
 func (t *AddTestSuite) TestMigrationAdded() {
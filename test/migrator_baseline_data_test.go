@@ -0,0 +1,183 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type BaselineDataTestSuite struct {
+	suite.Suite
+	db              *sql.DB
+	migrationFolder string
+}
+
+func TestBaselineDataTestSuite(t *testing.T) {
+	suite.Run(t, new(BaselineDataTestSuite))
+}
+
+func (suite *BaselineDataTestSuite) SetupTest() {
+	suite.migrationFolder = suite.T().TempDir()
+	suite.db = initMemorySqlite()
+
+	statements := []string{
+		"CREATE TABLE lookup_statuses (id INTEGER PRIMARY KEY, name TEXT NOT NULL, description TEXT)",
+		"CREATE TABLE lookup_currencies (code TEXT PRIMARY KEY, symbol BLOB)",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, status_id INTEGER REFERENCES lookup_statuses (id))",
+		"INSERT INTO lookup_statuses (id, name, description) VALUES (1, 'active', NULL), (2, 'it''s pending', 'multi\nline;')",
+		"INSERT INTO lookup_currencies (code, symbol) VALUES ('EUR', X'e282ac'), ('BIN', X'ff00fe')",
+		"INSERT INTO users (id, status_id) VALUES (1, 1)",
+	}
+	for i := 3; i <= 250; i++ {
+		statements = append(statements, fmt.Sprintf("INSERT INTO lookup_statuses (id, name) VALUES (%d, 'status %d')", i, i))
+	}
+
+	for _, statement := range statements {
+		_, err := suite.db.Exec(statement)
+		suite.NoError(err)
+	}
+}
+
+func (suite *BaselineDataTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *BaselineDataTestSuite) TestDataOfMatchingTablesIsRestored() {
+	m := newTestMigrator(t.db, t.migrationFolder, migrator.WithBaselineData("lookup_*"))
+	err := m.SaveBaseline()
+	t.NoError(err)
+
+	baseline, err := os.ReadFile(filepath.Join(t.migrationFolder, "baseline.sql"))
+	t.NoError(err)
+	t.Equal(3, strings.Count(string(baseline), `INSERT INTO "lookup_statuses"`))
+	t.NotContains(string(baseline), `INSERT INTO "users"`)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(freshDB, t.migrationFolder).LoadBaseline()
+	t.NoError(err)
+
+	count, err := rowCountInTable(freshDB, "lookup_statuses")
+	t.NoError(err)
+	t.Equal(250, count)
+
+	count, err = rowCountInTable(freshDB, "users")
+	t.NoError(err)
+	t.Equal(0, count)
+
+	var name string
+	var description sql.NullString
+	err = freshDB.QueryRow("SELECT name, description FROM lookup_statuses WHERE id = 2").Scan(&name, &description)
+	t.NoError(err)
+	t.Equal("it's pending", name)
+	t.Equal("multi\nline;", description.String)
+
+	err = freshDB.QueryRow("SELECT description FROM lookup_statuses WHERE id = 1").Scan(&description)
+	t.NoError(err)
+	t.False(description.Valid)
+
+	var symbol []byte
+	err = freshDB.QueryRow("SELECT symbol FROM lookup_currencies WHERE code = 'BIN'").Scan(&symbol)
+	t.NoError(err)
+	t.Equal([]byte{0xff, 0x00, 0xfe}, symbol)
+}
+
+func (t *BaselineDataTestSuite) TestDriftIgnoresBaselineData() {
+	m := newTestMigrator(t.db, t.migrationFolder, migrator.WithBaselineData("lookup_statuses"))
+	err := m.SaveBaseline()
+	t.NoError(err)
+
+	drifts, err := m.DetectDrift()
+	t.NoError(err)
+	t.Empty(drifts)
+}
+
+func (t *BaselineDataTestSuite) TestInvalidPattern() {
+	_, err := migrator.New(t.db, migrator.WithMigrationPath(t.migrationFolder), migrator.WithBaselineData("lookup_["))
+	t.Error(err)
+}
+
+func (t *BaselineDataTestSuite) TestMultiLineValuesRoundTrip() {
+	values := []string{"para1\n\npara2", "x\n-- not a comment", "end;\nnext", "windows\r\nline", `back\slash's`}
+	roundTrip := func(db *sql.DB, freshDB *sql.DB) {
+		_, err := db.Exec("CREATE TABLE notes (id INTEGER PRIMARY KEY, body VARCHAR)")
+		t.NoError(err)
+		for i, value := range values {
+			_, err = db.Exec("INSERT INTO notes (id, body) VALUES (?, ?)", i+1, value)
+			t.NoError(err)
+		}
+
+		folder := t.T().TempDir()
+		err = newTestMigrator(db, folder, migrator.WithBaselineData("notes")).SaveBaseline()
+		t.NoError(err)
+
+		err = newTestMigrator(freshDB, folder).LoadBaseline()
+		t.NoError(err)
+
+		for i, value := range values {
+			var body string
+			err = freshDB.QueryRow("SELECT body FROM notes WHERE id = ?", i+1).Scan(&body)
+			t.NoError(err)
+			t.Equal(value, body)
+		}
+	}
+
+	sqliteDB := initMemorySqlite()
+	defer sqliteDB.Close()
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+	roundTrip(sqliteDB, freshDB)
+
+	duckDB := initMemoryDuckDB()
+	defer duckDB.Close()
+	freshDuckDB := initMemoryDuckDB()
+	defer freshDuckDB.Close()
+	roundTrip(duckDB, freshDuckDB)
+}
+
+func (t *BaselineDataTestSuite) TestDataAfterViewIsRestored() {
+	_, err := t.db.Exec("CREATE VIEW status_names AS SELECT name FROM lookup_statuses")
+	t.NoError(err)
+
+	m := newTestMigrator(t.db, t.migrationFolder, migrator.WithBaselineData("lookup_statuses"))
+	err = m.SaveBaseline()
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+	freshMigrator := newTestMigrator(freshDB, t.migrationFolder)
+
+	// Three tables, the view, then the three inserts of the data
+	statements, err := freshMigrator.DryRunBaseline()
+	t.NoError(err)
+	t.Len(statements, 7)
+
+	err = freshMigrator.LoadBaseline()
+	t.NoError(err)
+
+	count, err := rowCountInTable(freshDB, "status_names")
+	t.NoError(err)
+	t.Equal(250, count)
+}
+
+func (t *BaselineDataTestSuite) TestUnterminatedStatementIsReported() {
+	err := os.WriteFile(
+		filepath.Join(t.migrationFolder, "baseline.sql"),
+		[]byte("-- migrator:object table users\nCREATE TABLE users (id INTEGER)\n"),
+		0o644,
+	)
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(freshDB, t.migrationFolder).LoadBaseline()
+	t.ErrorContains(err, "unterminated statement of table users")
+}
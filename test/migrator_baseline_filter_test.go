@@ -0,0 +1,152 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type BaselineFilterTestSuite struct {
+	suite.Suite
+	db              *sql.DB
+	migrationFolder string
+}
+
+func TestBaselineFilterTestSuite(t *testing.T) {
+	suite.Run(t, new(BaselineFilterTestSuite))
+}
+
+func (suite *BaselineFilterTestSuite) SetupTest() {
+	suite.migrationFolder = suite.T().TempDir()
+	err := copyFolder(testSquashFixtureFolder, suite.migrationFolder)
+	suite.NoError(err)
+
+	suite.db = initMemorySqlite()
+	err = newTestMigrator(suite.db, suite.migrationFolder).Migrate(0)
+	suite.NoError(err)
+}
+
+func (suite *BaselineFilterTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *BaselineFilterTestSuite) baseline() string {
+	baseline, err := os.ReadFile(filepath.Join(t.migrationFolder, "baseline.sql"))
+	t.NoError(err)
+
+	return string(baseline)
+}
+
+func (t *BaselineFilterTestSuite) TestTrackingTablesExcludedByDefault() {
+	_, err := t.db.Exec("CREATE TABLE counters (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT)")
+	t.NoError(err)
+	_, err = t.db.Exec("INSERT INTO counters (name) VALUES ('first')")
+	t.NoError(err)
+
+	err = newTestMigrator(t.db, t.migrationFolder).SaveBaseline()
+	t.NoError(err)
+
+	baseline := t.baseline()
+	t.Contains(baseline, "-- migrator:object table users\n")
+	t.Contains(baseline, "-- migrator:object table counters\n")
+	t.NotContains(baseline, tablePrefix+"_migrations")
+	t.NotContains(baseline, tablePrefix+"_migration_reports")
+	t.NotContains(baseline, "sqlite_sequence")
+
+	// The fresh database has its tracking tables before the baseline is loaded
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+	freshMigrator := newTestMigrator(freshDB, t.migrationFolder)
+	_, err = freshMigrator.Status()
+	t.NoError(err)
+
+	err = freshMigrator.LoadBaseline()
+	t.NoError(err)
+}
+
+func (t *BaselineFilterTestSuite) TestIncludeAndExclude() {
+	m := newTestMigrator(
+		t.db,
+		t.migrationFolder,
+		migrator.WithBaselineInclude(migrator.ObjectFilter{ObjectType: migrator.ObjectTypeTable, Regexp: regexp.MustCompile("^(users|roles|posts)$")}),
+		migrator.WithBaselineExclude(migrator.ObjectFilter{Pattern: "ro*"}),
+	)
+
+	err := m.SaveBaseline()
+	t.NoError(err)
+
+	baseline := t.baseline()
+	t.Contains(baseline, "-- migrator:object table users\n")
+	t.Contains(baseline, "-- migrator:object table posts\n")
+	t.NotContains(baseline, "-- migrator:object table roles\n")
+}
+
+func (t *BaselineFilterTestSuite) TestIncludedTrackingTable() {
+	m := newTestMigrator(
+		t.db,
+		t.migrationFolder,
+		migrator.WithBaselineInclude(migrator.ObjectFilter{Pattern: "*"}),
+	)
+
+	err := m.SaveBaseline()
+	t.NoError(err)
+	t.Contains(t.baseline(), "-- migrator:object table "+tablePrefix+"_migrations\n")
+}
+
+func (t *BaselineFilterTestSuite) TestFilterAppliesToLoad() {
+	err := newTestMigrator(t.db, t.migrationFolder).SaveBaseline()
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(
+		freshDB,
+		t.migrationFolder,
+		migrator.WithBaselineExclude(migrator.ObjectFilter{ObjectType: migrator.ObjectTypeTable, Pattern: "posts"}),
+	).LoadBaseline()
+	t.NoError(err)
+
+	tableCount, err := tableCountInDatabase(freshDB)
+	t.NoError(err)
+	t.Equal(4, tableCount)
+
+	var count int
+	err = freshDB.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE name = 'posts'").Scan(&count)
+	t.NoError(err)
+	t.Equal(0, count)
+}
+
+func (t *BaselineFilterTestSuite) TestFilterAppliesToDrift() {
+	db := initMemorySqlite()
+	defer db.Close()
+
+	m := newTestMigrator(
+		db,
+		t.migrationFolder,
+		migrator.WithSchemaSnapshot(filepath.Join(t.migrationFolder, "snapshot.sql")),
+		migrator.WithBaselineExclude(migrator.ObjectFilter{Pattern: "scratch_*"}),
+	)
+	err := m.Migrate(0)
+	t.NoError(err)
+
+	_, err = db.Exec("CREATE TABLE scratch_import (id INTEGER)")
+	t.NoError(err)
+
+	drifts, err := m.DetectDrift()
+	t.NoError(err)
+	t.Empty(drifts)
+}
+
+func (t *BaselineFilterTestSuite) TestInvalidFilter() {
+	_, err := migrator.New(t.db, migrator.WithBaselineExclude(migrator.ObjectFilter{}))
+	t.Error(err)
+
+	_, err = migrator.New(t.db, migrator.WithBaselineInclude(migrator.ObjectFilter{Pattern: "users_["}))
+	t.Error(err)
+}
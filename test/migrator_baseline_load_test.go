@@ -0,0 +1,228 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/olbrichattila/godbmigrator/config"
+	"github.com/stretchr/testify/suite"
+)
+
+type BaselineLoadTestSuite struct {
+	suite.Suite
+	db              *sql.DB
+	migrationFolder string
+}
+
+func TestBaselineLoadTestSuite(t *testing.T) {
+	suite.Run(t, new(BaselineLoadTestSuite))
+}
+
+func (suite *BaselineLoadTestSuite) SetupTest() {
+	suite.migrationFolder = suite.T().TempDir()
+	err := copyFolder(testSquashFixtureFolder, suite.migrationFolder)
+	suite.NoError(err)
+
+	suite.db = initMemorySqlite()
+	m := newTestMigrator(suite.db, suite.migrationFolder)
+	err = m.Migrate(0)
+	suite.NoError(err)
+
+	err = m.SaveBaseline()
+	suite.NoError(err)
+}
+
+func (suite *BaselineLoadTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *BaselineLoadTestSuite) TestLoadIntoNonEmptyDatabaseIsRefused() {
+	err := newTestMigrator(t.db, t.migrationFolder).LoadBaseline()
+	t.ErrorIs(err, migrator.ErrDatabaseNotEmpty)
+}
+
+func (t *BaselineLoadTestSuite) TestForcedLoad() {
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	_, err := freshDB.Exec("CREATE TABLE scratch (id INTEGER)")
+	t.NoError(err)
+
+	err = newTestMigrator(freshDB, t.migrationFolder).LoadBaseline()
+	t.ErrorIs(err, migrator.ErrDatabaseNotEmpty)
+
+	err = newTestMigrator(freshDB, t.migrationFolder, migrator.WithForceBaselineLoad()).LoadBaseline()
+	t.NoError(err)
+
+	count, err := countInSqliteMasterForType(freshDB, "table")
+	t.NoError(err)
+	t.Equal(6, count)
+}
+
+func (t *BaselineLoadTestSuite) TestDryRun() {
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	statements, err := newTestMigrator(freshDB, t.migrationFolder).DryRunBaseline()
+	t.NoError(err)
+	t.Len(statements, 3)
+	t.Equal(migrator.ObjectTypeTable, statements[0].ObjectType)
+	t.Equal("posts", statements[0].Name)
+	t.Contains(statements[0].SQL, "CREATE TABLE posts")
+
+	count, err := countInSqliteMasterForType(freshDB, "table")
+	t.NoError(err)
+	t.Equal(0, count)
+}
+
+func (t *BaselineLoadTestSuite) TestFailedLoadIsRolledBack() {
+	baselinePath := filepath.Join(t.migrationFolder, "baseline.sql")
+	baseline, err := os.ReadFile(baselinePath)
+	t.NoError(err)
+
+	baseline = append(baseline, []byte("-- migrator:object table broken\nCREATE TABLE broken (;\n")...)
+	err = os.WriteFile(baselinePath, baseline, 0o644)
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(freshDB, t.migrationFolder).LoadBaseline()
+	var objectErr *migrator.BaselineObjectError
+	t.True(errors.As(err, &objectErr))
+	t.Equal(migrator.ObjectTypeTable, objectErr.ObjectType)
+	t.Equal("broken", objectErr.Name)
+
+	// Only the migration tracking tables remain, they are created before the load to record its version with it
+	count, err := countInSqliteMasterForType(freshDB, "table")
+	t.NoError(err)
+	t.Equal(2, count)
+
+	statuses, err := newTestMigrator(freshDB, t.migrationFolder).Status()
+	t.NoError(err)
+	t.Equal(migrator.StatusPending, statuses[0].Status)
+}
+
+func (t *BaselineLoadTestSuite) TestProgressEvents() {
+	m := newTestMigrator(t.db, t.migrationFolder)
+	var saved []string
+	m.SubscribeToMessages(func(eventType int, message string) {
+		if eventType == config.BaselineSavingObject {
+			saved = append(saved, message)
+		}
+	})
+
+	err := m.SaveBaseline()
+	t.NoError(err)
+	t.Equal([]string{"1/3 table posts", "2/3 table roles", "3/3 table users"}, saved)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	freshMigrator := newTestMigrator(freshDB, t.migrationFolder)
+	var loaded []string
+	freshMigrator.SubscribeToMessages(func(eventType int, message string) {
+		if eventType == config.BaselineLoadingObject {
+			loaded = append(loaded, message)
+		}
+	})
+
+	err = freshMigrator.LoadBaseline()
+	t.NoError(err)
+	t.Equal(saved, loaded)
+}
+
+func (t *BaselineLoadTestSuite) TestManyObjectsAreRestored() {
+	for i := 0; i < 200; i++ {
+		statements := []string{
+			fmt.Sprintf("CREATE TABLE items_%03d (id INTEGER PRIMARY KEY, name TEXT)", i),
+			fmt.Sprintf("CREATE INDEX idx_items_%03d_name ON items_%03d (name)", i, i),
+			fmt.Sprintf("CREATE VIEW item_names_%03d AS SELECT name FROM items_%03d", i, i),
+		}
+		for _, statement := range statements {
+			_, err := t.db.Exec(statement)
+			t.NoError(err)
+		}
+	}
+
+	err := newTestMigrator(t.db, t.migrationFolder).SaveBaseline()
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	freshMigrator := newTestMigrator(freshDB, t.migrationFolder)
+	err = freshMigrator.LoadBaseline()
+	t.NoError(err)
+
+	for objectType, expected := range map[string]int{"index": 200, "view": 200} {
+		count, err := countInSqliteMasterForType(freshDB, objectType)
+		t.NoError(err)
+		t.Equal(expected, count)
+	}
+
+	drifts, err := freshMigrator.DetectDrift()
+	t.NoError(err)
+	t.Empty(drifts)
+}
+
+func (t *BaselineLoadTestSuite) TestPostgresStatementsAfterRoutinesAreRead() {
+	// The PostgreSQL baseline writes policies, grants and sequence positions after views and functions
+	baseline := `-- migrator:baseline 2024-01-01_10_00_00-documents.sql
+-- migrator:object table documents
+CREATE TABLE "documents" (
+    "id" integer NOT NULL,
+    "owner" text NOT NULL
+);
+-- migrator:object view owners
+DELIMITER ;
+CREATE VIEW "owners" AS SELECT DISTINCT owner FROM documents;
+DELIMITER ;;
+-- migrator:object function touch
+DELIMITER ;
+CREATE OR REPLACE FUNCTION touch() RETURNS trigger LANGUAGE plpgsql AS $function$
+BEGIN
+    RETURN NEW;
+END;
+$function$
+DELIMITER ;;
+-- migrator:object policy documents
+ALTER TABLE "documents" ENABLE ROW LEVEL SECURITY;
+CREATE POLICY owner_only ON "documents" USING (owner = current_user);
+-- migrator:object grant documents
+GRANT SELECT ON "documents" TO reporting;
+-- migrator:object data documents
+INSERT INTO "documents" ("id", "owner") OVERRIDING SYSTEM VALUE VALUES
+(1, 'admin');
+SELECT setval('documents_id_seq', 1, true);
+`
+	folder := t.T().TempDir()
+	err := os.WriteFile(filepath.Join(folder, "baseline.sql"), []byte(baseline), 0o644)
+	t.NoError(err)
+
+	m := newTestMigrator(t.db, folder, migrator.WithDialect(migrator.DialectPostgres))
+	statements, err := m.DryRunBaseline()
+	t.NoError(err)
+
+	var objects []string
+	for _, statement := range statements {
+		objects = append(objects, statement.ObjectType+" "+statement.Name)
+	}
+	t.Equal([]string{
+		"table documents",
+		"view owners",
+		"function touch",
+		"policy documents",
+		"policy documents",
+		"grant documents",
+		"data documents",
+		"data documents",
+	}, objects)
+	t.Equal("CREATE POLICY owner_only ON \"documents\" USING (owner = current_user);\n", statements[4].SQL)
+	t.Equal("SELECT setval('documents_id_seq', 1, true);\n", statements[7].SQL)
+}
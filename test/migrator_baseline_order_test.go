@@ -0,0 +1,101 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type BaselineOrderTestSuite struct {
+	suite.Suite
+	db              *sql.DB
+	migrationFolder string
+	migrator        migrator.DBMigrator
+}
+
+func TestBaselineOrderTestSuite(t *testing.T) {
+	suite.Run(t, new(BaselineOrderTestSuite))
+}
+
+func (suite *BaselineOrderTestSuite) SetupTest() {
+	suite.migrationFolder = suite.T().TempDir()
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(suite.db, suite.migrationFolder)
+}
+
+func (suite *BaselineOrderTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *BaselineOrderTestSuite) TestDependentObjectsLoadInOrder() {
+	// Recreating the objects moves them after their dependents in the catalog
+	statements := []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE VIEW user_names AS SELECT name FROM users",
+		"CREATE VIEW sorted_user_names AS SELECT name FROM user_names ORDER BY name",
+		"CREATE TRIGGER users_insert AFTER INSERT ON users BEGIN UPDATE users SET name = trim(name) WHERE id = NEW.id; END",
+		"DROP VIEW user_names",
+		"CREATE VIEW user_names AS SELECT name FROM users",
+		"ALTER TABLE users RENAME TO users_old",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"DROP TABLE users_old",
+		"CREATE TRIGGER users_insert AFTER INSERT ON users BEGIN UPDATE users SET name = trim(name) WHERE id = NEW.id; END",
+	}
+	for _, statement := range statements {
+		_, err := t.db.Exec(statement)
+		t.NoError(err)
+	}
+
+	err := t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	baseline, err := os.ReadFile(filepath.Join(t.migrationFolder, "baseline.sql"))
+	t.NoError(err)
+	t.Less(
+		strings.Index(string(baseline), "CREATE VIEW user_names"),
+		strings.Index(string(baseline), "CREATE VIEW sorted_user_names"),
+	)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(freshDB, t.migrationFolder).LoadBaseline()
+	t.NoError(err)
+
+	count, err := countInSqliteMasterForType(freshDB, "view")
+	t.NoError(err)
+	t.Equal(2, count)
+
+	count, err = countInSqliteMasterForType(freshDB, "trigger")
+	t.NoError(err)
+	t.Equal(1, count)
+}
+
+func (t *BaselineOrderTestSuite) TestForeignKeyCycleLoads() {
+	statements := []string{
+		"CREATE TABLE teams (id INTEGER PRIMARY KEY, captain_id INTEGER REFERENCES players (id))",
+		"CREATE TABLE players (id INTEGER PRIMARY KEY, team_id INTEGER REFERENCES teams (id))",
+	}
+	for _, statement := range statements {
+		_, err := t.db.Exec(statement)
+		t.NoError(err)
+	}
+
+	err := t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(freshDB, t.migrationFolder).LoadBaseline()
+	t.NoError(err)
+
+	tableCount, err := tableCountInDatabase(freshDB)
+	t.NoError(err)
+	t.Equal(2, tableCount)
+}
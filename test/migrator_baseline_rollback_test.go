@@ -0,0 +1,208 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type BaselineRollbackTestSuite struct {
+	suite.Suite
+	db              *sql.DB
+	migrationFolder string
+	migrator        migrator.DBMigrator
+}
+
+func TestBaselineRollbackTestSuite(t *testing.T) {
+	suite.Run(t, new(BaselineRollbackTestSuite))
+}
+
+func (suite *BaselineRollbackTestSuite) SetupTest() {
+	suite.migrationFolder = suite.T().TempDir()
+	err := copyFolder(testSquashFixtureFolder, suite.migrationFolder)
+	suite.NoError(err)
+
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(suite.db, suite.migrationFolder)
+}
+
+func (suite *BaselineRollbackTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *BaselineRollbackTestSuite) TestRollbackDropsInReverseOrder() {
+	statements := []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users (id))",
+		"CREATE INDEX idx_posts_user ON posts (user_id)",
+		"CREATE VIEW user_names AS SELECT name FROM users",
+		"CREATE TRIGGER users_deleted AFTER DELETE ON users BEGIN DELETE FROM posts WHERE user_id = old.id; END",
+	}
+	for _, statement := range statements {
+		_, err := t.db.Exec(statement)
+		t.NoError(err)
+	}
+
+	baselineFolder := t.T().TempDir()
+	err := t.migrator.SaveBaseline(baselineFolder)
+	t.NoError(err)
+
+	rollback, err := os.ReadFile(filepath.Join(baselineFolder, "baseline-rollback.sql"))
+	t.NoError(err)
+	t.Equal(`-- migrator:object trigger users_deleted
+DROP TRIGGER IF EXISTS "users_deleted";
+-- migrator:object view user_names
+DROP VIEW IF EXISTS "user_names";
+-- migrator:object index idx_posts_user
+DROP INDEX IF EXISTS "idx_posts_user";
+-- migrator:object table posts
+DROP TABLE IF EXISTS "posts";
+-- migrator:object table users
+DROP TABLE IF EXISTS "users";
+`, string(rollback))
+
+	err = t.migrator.RollbackBaseline(baselineFolder)
+	t.NoError(err)
+
+	objectCount, err := countInSqliteMasterForType(t.db, "view")
+	t.NoError(err)
+	t.Equal(0, objectCount)
+
+	objectCount, err = countInSqliteMasterForType(t.db, "trigger")
+	t.NoError(err)
+	t.Equal(0, objectCount)
+
+	err = t.migrator.LoadBaseline(baselineFolder)
+	t.NoError(err)
+}
+
+func (t *BaselineRollbackTestSuite) TestRollbackSquashedBaseline() {
+	err := t.migrator.Migrate(2)
+	t.NoError(err)
+
+	err = t.migrator.Squash(testSquashUpTo)
+	t.NoError(err)
+	t.FileExists(filepath.Join(t.migrationFolder, "baseline-rollback.sql"))
+
+	err = t.migrator.Migrate(0)
+	t.NoError(err)
+
+	err = t.migrator.RollbackBaseline()
+	t.ErrorContains(err, "migration 2024-01-03_10_00_00-posts.sql is applied after it")
+
+	err = t.migrator.Rollback(0)
+	t.NoError(err)
+
+	err = t.migrator.RollbackBaseline()
+	t.NoError(err)
+
+	// Only the migration tracking tables remain
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(2, tableCount)
+
+	err = t.migrator.Migrate(0)
+	t.ErrorContains(err, "run LoadBaseline first")
+
+	err = t.migrator.LoadBaseline()
+	t.NoError(err)
+
+	err = t.migrator.Migrate(0)
+	t.NoError(err)
+
+	tableCount, err = tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(5, tableCount)
+}
+
+func (t *BaselineRollbackTestSuite) TestSplitBaselineRollback() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	m := newTestMigrator(t.db, t.migrationFolder, migrator.WithSplitBaseline())
+	err = m.SaveBaseline()
+	t.NoError(err)
+	t.FileExists(filepath.Join(t.migrationFolder, "baseline-rollback.sql"))
+
+	err = m.Rollback(0)
+	t.NoError(err)
+
+	err = m.LoadBaseline()
+	t.NoError(err)
+
+	err = m.RollbackBaseline()
+	t.NoError(err)
+
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(2, tableCount)
+}
+
+func (t *BaselineRollbackTestSuite) TestRollbackWithoutRollbackFile() {
+	err := t.migrator.RollbackBaseline()
+	t.ErrorContains(err, "baseline-rollback.sql does not exist")
+}
+
+func (t *BaselineRollbackTestSuite) TestDuckDBRollbackDropsSequences() {
+	db := initMemoryDuckDB()
+	defer db.Close()
+
+	statements := []string{
+		"CREATE SEQUENCE user_ids",
+		"CREATE TABLE \"user accounts\" (id INTEGER PRIMARY KEY DEFAULT nextval('user_ids'), name VARCHAR)",
+		"CREATE VIEW account_names AS SELECT name FROM \"user accounts\"",
+	}
+	for _, statement := range statements {
+		_, err := db.Exec(statement)
+		t.NoError(err)
+	}
+
+	baselineFolder := t.T().TempDir()
+	m := newTestMigrator(db, baselineFolder)
+	err := m.SaveBaseline()
+	t.NoError(err)
+
+	err = m.RollbackBaseline()
+	t.NoError(err)
+
+	var count int
+	err = db.QueryRow("SELECT (SELECT count(*) FROM duckdb_sequences()) + (SELECT count(*) FROM duckdb_views() WHERE NOT internal) + " +
+		"(SELECT count(*) FROM duckdb_tables() WHERE table_name NOT LIKE 'olb_%')").Scan(&count)
+	t.NoError(err)
+	t.Equal(0, count)
+}
+
+func (t *BaselineRollbackTestSuite) TestRollbackOfBaselineWithLongLines() {
+	_, err := t.db.Exec("CREATE TABLE documents (id INTEGER PRIMARY KEY, body TEXT)")
+	t.NoError(err)
+
+	// A data row is one line of the baseline, longer than the default line limit of the reader
+	body := strings.Repeat("x", 100*1024)
+	_, err = t.db.Exec("INSERT INTO documents (id, body) VALUES (1, ?)", body)
+	t.NoError(err)
+
+	baselineFolder := t.T().TempDir()
+	m := newTestMigrator(t.db, baselineFolder, migrator.WithBaselineData("documents"))
+	err = m.SaveBaseline()
+	t.NoError(err)
+
+	rollback, err := os.ReadFile(filepath.Join(baselineFolder, "baseline-rollback.sql"))
+	t.NoError(err)
+	t.Equal("-- migrator:object table documents\nDROP TABLE IF EXISTS \"documents\";\n", string(rollback))
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(freshDB, baselineFolder).LoadBaseline()
+	t.NoError(err)
+
+	var loaded string
+	err = freshDB.QueryRow("SELECT body FROM documents WHERE id = 1").Scan(&loaded)
+	t.NoError(err)
+	t.Equal(body, loaded)
+}
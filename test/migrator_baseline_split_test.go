@@ -0,0 +1,190 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type BaselineSplitTestSuite struct {
+	suite.Suite
+	db              *sql.DB
+	migrationFolder string
+	migrator        migrator.DBMigrator
+}
+
+func TestBaselineSplitTestSuite(t *testing.T) {
+	suite.Run(t, new(BaselineSplitTestSuite))
+}
+
+func (suite *BaselineSplitTestSuite) SetupTest() {
+	suite.migrationFolder = suite.T().TempDir()
+	err := copyFolder(testSquashFixtureFolder, suite.migrationFolder)
+	suite.NoError(err)
+
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(suite.db, suite.migrationFolder, migrator.WithSplitBaseline())
+
+	err = suite.migrator.Migrate(2)
+	suite.NoError(err)
+
+	statements := []string{
+		"CREATE INDEX idx_users_name ON users (name)   ",
+		"CREATE VIEW user_names AS SELECT name FROM users",
+		"CREATE VIEW all_names AS SELECT name FROM user_names UNION SELECT name FROM roles",
+	}
+	for _, statement := range statements {
+		_, err := suite.db.Exec(statement)
+		suite.NoError(err)
+	}
+}
+
+func (suite *BaselineSplitTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *BaselineSplitTestSuite) TestSaveWritesOneFilePerObject() {
+	err := t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	manifest, err := os.ReadFile(filepath.Join(t.migrationFolder, "baseline", "manifest.txt"))
+	t.NoError(err)
+	t.Equal(
+		"-- migrator:baseline 2024-01-02_10_00_00-roles.sql\n"+
+			"tables/roles.sql\n"+
+			"tables/users.sql\n"+
+			"indexes/idx_users_name.sql\n"+
+			"views/user_names.sql\n"+
+			"views/all_names.sql\n",
+		string(manifest),
+	)
+
+	index, err := os.ReadFile(filepath.Join(t.migrationFolder, "baseline", "indexes", "idx_users_name.sql"))
+	t.NoError(err)
+	t.Equal("-- migrator:object index idx_users_name\nCREATE INDEX idx_users_name ON users (name);\n", string(index))
+
+	_, err = os.Stat(filepath.Join(t.migrationFolder, "baseline.sql"))
+	t.True(os.IsNotExist(err))
+}
+
+func (t *BaselineSplitTestSuite) TestSaveIsDeterministic() {
+	err := t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	first, err := os.ReadFile(filepath.Join(t.migrationFolder, "baseline", "manifest.txt"))
+	t.NoError(err)
+
+	_, err = t.db.Exec("DROP VIEW all_names")
+	t.NoError(err)
+
+	err = t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	_, err = os.Stat(filepath.Join(t.migrationFolder, "baseline", "views", "all_names.sql"))
+	t.True(os.IsNotExist(err))
+
+	_, err = t.db.Exec("CREATE VIEW all_names AS SELECT name FROM user_names UNION SELECT name FROM roles")
+	t.NoError(err)
+
+	err = t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	second, err := os.ReadFile(filepath.Join(t.migrationFolder, "baseline", "manifest.txt"))
+	t.NoError(err)
+	t.Equal(string(first), string(second))
+}
+
+func (t *BaselineSplitTestSuite) TestLoadFollowsManifest() {
+	err := t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+	freshMigrator := newTestMigrator(freshDB, t.migrationFolder)
+
+	err = freshMigrator.LoadBaseline()
+	t.NoError(err)
+
+	count, err := countInSqliteMasterForType(freshDB, "view")
+	t.NoError(err)
+	t.Equal(2, count)
+
+	statuses, err := freshMigrator.Status()
+	t.NoError(err)
+	t.Equal(migrator.StatusApplied, statuses[1].Status)
+	t.Equal(migrator.StatusPending, statuses[2].Status)
+
+	drifts, err := freshMigrator.DetectDrift()
+	t.NoError(err)
+	t.Empty(drifts)
+}
+
+func (t *BaselineSplitTestSuite) TestSingleFileReplacesSplitBaseline() {
+	err := t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	err = newTestMigrator(t.db, t.migrationFolder).SaveBaseline()
+	t.NoError(err)
+
+	_, err = os.Stat(filepath.Join(t.migrationFolder, "baseline"))
+	t.True(os.IsNotExist(err))
+
+	_, err = os.Stat(filepath.Join(t.migrationFolder, "baseline.sql"))
+	t.NoError(err)
+}
+
+func (t *BaselineSplitTestSuite) TestSplitKeepsWhitespaceInsideStatements() {
+	_, err := t.db.Exec("CREATE VIEW padded AS SELECT 'trailing  \n\tindented' AS value  \r\n")
+	t.NoError(err)
+
+	err = t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	view, err := os.ReadFile(filepath.Join(t.migrationFolder, "baseline", "views", "padded.sql"))
+	t.NoError(err)
+	t.Contains(string(view), "SELECT 'trailing  \n\tindented' AS value\n")
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(freshDB, t.migrationFolder).LoadBaseline()
+	t.NoError(err)
+
+	var value string
+	err = freshDB.QueryRow("SELECT value FROM padded").Scan(&value)
+	t.NoError(err)
+	t.Equal("trailing  \n\tindented", value)
+}
+
+func (t *BaselineSplitTestSuite) TestObjectsWithSameFileNameKeepTheirFiles() {
+	_, err := t.db.Exec(`CREATE VIEW "user names" AS SELECT name FROM roles`)
+	t.NoError(err)
+
+	err = t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	view, err := os.ReadFile(filepath.Join(t.migrationFolder, "baseline", "views", "user_names_2.sql"))
+	t.NoError(err)
+	t.Contains(string(view), "-- migrator:object view user names\n")
+	t.NotContains(string(view), "view user_names")
+
+	manifest, err := os.ReadFile(filepath.Join(t.migrationFolder, "baseline", "manifest.txt"))
+	t.NoError(err)
+	t.Equal(1, strings.Count(string(manifest), "views/user_names.sql\n"))
+	t.Equal(1, strings.Count(string(manifest), "views/user_names_2.sql\n"))
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(freshDB, t.migrationFolder).LoadBaseline()
+	t.NoError(err)
+
+	count, err := countInSqliteMasterForType(freshDB, "view")
+	t.NoError(err)
+	t.Equal(3, count)
+}
@@ -0,0 +1,200 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type BaselineTranslateTestSuite struct {
+	suite.Suite
+	db       *sql.DB
+	migrator migrator.DBMigrator
+}
+
+func TestBaselineTranslateTestSuite(t *testing.T) {
+	suite.Run(t, new(BaselineTranslateTestSuite))
+}
+
+func (suite *BaselineTranslateTestSuite) SetupTest() {
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(suite.db, suite.T().TempDir())
+
+	statements := []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, email VARCHAR(120) NOT NULL UNIQUE, active BOOLEAN NOT NULL DEFAULT 1, " +
+			"created_at DATETIME DEFAULT CURRENT_TIMESTAMP, bio TEXT, score REAL DEFAULT 0.5, nickname DEFAULT 'guest')",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER NOT NULL REFERENCES users (id), title VARCHAR(200), body TEXT)",
+		"CREATE INDEX idx_posts_user ON posts (user_id)",
+		"CREATE UNIQUE INDEX idx_posts_title ON posts (user_id, title)",
+		"CREATE INDEX idx_posts_body ON posts (body)",
+		"CREATE VIEW active_users AS SELECT \"email\" FROM users WHERE active = 1",
+		"CREATE TRIGGER users_deleted AFTER DELETE ON users BEGIN DELETE FROM posts WHERE user_id = old.id; END",
+	}
+	for _, statement := range statements {
+		_, err := suite.db.Exec(statement)
+		suite.NoError(err)
+	}
+}
+
+func (suite *BaselineTranslateTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *BaselineTranslateTestSuite) TestSqliteToPostgres() {
+	folder := t.T().TempDir()
+	issues, err := t.migrator.SaveTranslatedBaseline(migrator.DialectPostgres, folder)
+	t.NoError(err)
+
+	baseline, err := os.ReadFile(filepath.Join(folder, "baseline.sql"))
+	t.NoError(err)
+	t.Equal(`-- migrator:object table users
+CREATE TABLE "users" (
+    "id" INTEGER GENERATED BY DEFAULT AS IDENTITY,
+    "email" VARCHAR(120) NOT NULL,
+    "active" BOOLEAN NOT NULL DEFAULT TRUE,
+    "created_at" TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    "bio" TEXT,
+    "score" REAL DEFAULT 0.5,
+    "nickname" TEXT DEFAULT 'guest',
+    PRIMARY KEY ("id"),
+    UNIQUE ("email")
+);
+-- migrator:object table posts
+CREATE TABLE "posts" (
+    "id" INTEGER GENERATED BY DEFAULT AS IDENTITY,
+    "user_id" INTEGER NOT NULL,
+    "title" VARCHAR(200),
+    "body" TEXT,
+    FOREIGN KEY ("user_id") REFERENCES "users" ("id"),
+    PRIMARY KEY ("id")
+);
+-- migrator:object index idx_posts_body
+CREATE INDEX "idx_posts_body" ON "posts" ("body");
+-- migrator:object index idx_posts_title
+CREATE UNIQUE INDEX "idx_posts_title" ON "posts" ("user_id", "title");
+-- migrator:object index idx_posts_user
+CREATE INDEX "idx_posts_user" ON "posts" ("user_id");
+-- migrator:object view active_users
+DELIMITER ;
+CREATE VIEW active_users AS SELECT "email" FROM users WHERE active = 1
+DELIMITER ;;
+`, string(baseline))
+
+	t.Equal([]migrator.TranslationIssue{
+		{ObjectType: migrator.ObjectTypeTable, Name: "users", Message: "column nickname has no type, it is translated to TEXT"},
+		{ObjectType: migrator.ObjectTypeView, Name: "active_users", Message: "the query is copied unchanged, review its functions and syntax"},
+		{ObjectType: migrator.ObjectTypeTrigger, Name: "users_deleted", Message: "triggers cannot be translated, rewrite it for pg"},
+	}, issues)
+
+	t.FileExists(filepath.Join(folder, "baseline-rollback.sql"))
+}
+
+func (t *BaselineTranslateTestSuite) TestSqliteToMySQL() {
+	folder := t.T().TempDir()
+	issues, err := t.migrator.SaveTranslatedBaseline(migrator.DialectMySQL, folder)
+	t.NoError(err)
+
+	baseline, err := os.ReadFile(filepath.Join(folder, "baseline.sql"))
+	t.NoError(err)
+	t.Contains(string(baseline), "    `id` INT AUTO_INCREMENT,\n")
+	t.Contains(string(baseline), "    `active` TINYINT(1) NOT NULL DEFAULT 1,\n")
+	t.Contains(string(baseline), "    `nickname` TEXT DEFAULT ('guest'),\n")
+	t.Contains(string(baseline), "CREATE VIEW active_users AS SELECT `email` FROM users WHERE active = 1\n")
+	t.Contains(issues, migrator.TranslationIssue{
+		ObjectType: migrator.ObjectTypeIndex,
+		Name:       "idx_posts_body",
+		Message:    "column body needs a prefix length to be indexed by mysql",
+	})
+}
+
+func (t *BaselineTranslateTestSuite) TestSqliteToDuckDB() {
+	folder := t.T().TempDir()
+	_, err := t.migrator.SaveTranslatedBaseline(migrator.DialectDuckDB, folder)
+	t.NoError(err)
+
+	duckDB := initMemoryDuckDB()
+	defer duckDB.Close()
+
+	m := newTestMigrator(duckDB, folder)
+	err = m.LoadBaseline()
+	t.NoError(err)
+
+	// The sequence of the primary key replaces the rowid of SQLite
+	_, err = duckDB.Exec("INSERT INTO users (email) VALUES ('a@example.com'), ('b@example.com')")
+	t.NoError(err)
+
+	var active bool
+	var maxID int
+	err = duckDB.QueryRow("SELECT bool_and(active), max(id) FROM users").Scan(&active, &maxID)
+	t.NoError(err)
+	t.True(active)
+	t.Equal(2, maxID)
+
+	err = m.RollbackBaseline()
+	t.NoError(err)
+}
+
+func (t *BaselineTranslateTestSuite) TestDuckDBToSqlite() {
+	duckDB := initMemoryDuckDB()
+	defer duckDB.Close()
+
+	statements := []string{
+		"CREATE SEQUENCE order_ids",
+		"CREATE TABLE orders (id BIGINT PRIMARY KEY DEFAULT nextval('order_ids'), total DECIMAL(10,2) NOT NULL DEFAULT 0, " +
+			"paid BOOLEAN DEFAULT false, placed_at TIMESTAMP WITH TIME ZONE, tags VARCHAR[])",
+	}
+	for _, statement := range statements {
+		_, err := duckDB.Exec(statement)
+		t.NoError(err)
+	}
+
+	folder := t.T().TempDir()
+	issues, err := newTestMigrator(duckDB, folder).SaveTranslatedBaseline(migrator.DialectSQLite, folder)
+	t.NoError(err)
+	t.Equal([]migrator.TranslationIssue{
+		{ObjectType: migrator.ObjectTypeTable, Name: "orders", Message: "column placed_at has a time zone, sqlite has no type keeping it"},
+		{ObjectType: migrator.ObjectTypeTable, Name: "orders", Message: "type VARCHAR[] of column tags is unknown, it is copied unchanged"},
+	}, issues)
+
+	baseline, err := os.ReadFile(filepath.Join(folder, "baseline.sql"))
+	t.NoError(err)
+	t.Contains(string(baseline), `CREATE TABLE "orders" (
+    "id" INTEGER PRIMARY KEY AUTOINCREMENT,
+    "total" NUMERIC(10,2) NOT NULL DEFAULT 0,
+    "paid" BOOLEAN DEFAULT 0,
+    "placed_at" DATETIME,
+    "tags" VARCHAR[]
+);
+`)
+
+	sqliteDB := initMemorySqlite()
+	defer sqliteDB.Close()
+
+	err = newTestMigrator(sqliteDB, folder).LoadBaseline()
+	t.NoError(err)
+
+	_, err = sqliteDB.Exec("INSERT INTO orders (total) VALUES (10)")
+	t.NoError(err)
+}
+
+func (t *BaselineTranslateTestSuite) TestUnsupportedDialect() {
+	_, err := t.migrator.SaveTranslatedBaseline(migrator.DialectFirebird, t.T().TempDir())
+	t.Error(err)
+}
+
+func (t *BaselineTranslateTestSuite) TestDataAndSplitOptionsAreRejected() {
+	folder := t.T().TempDir()
+
+	_, err := newTestMigrator(t.db, folder, migrator.WithBaselineData("users")).SaveTranslatedBaseline(migrator.DialectPostgres)
+	t.ErrorContains(err, "baseline data")
+
+	_, err = newTestMigrator(t.db, folder, migrator.WithSplitBaseline()).SaveTranslatedBaseline(migrator.DialectPostgres)
+	t.ErrorContains(err, "split baseline")
+
+	_, err = os.Stat(filepath.Join(folder, "baseline.sql"))
+	t.ErrorIs(err, os.ErrNotExist)
+}
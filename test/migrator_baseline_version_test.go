@@ -0,0 +1,123 @@
+package migrator_test
+
+import (
+	"bufio"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type BaselineVersionTestSuite struct {
+	suite.Suite
+	db              *sql.DB
+	migrationFolder string
+	migrator        migrator.DBMigrator
+}
+
+func TestBaselineVersionTestSuite(t *testing.T) {
+	suite.Run(t, new(BaselineVersionTestSuite))
+}
+
+func (suite *BaselineVersionTestSuite) SetupTest() {
+	suite.migrationFolder = suite.T().TempDir()
+	err := copyFolder(testSquashFixtureFolder, suite.migrationFolder)
+	suite.NoError(err)
+
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(suite.db, suite.migrationFolder)
+}
+
+func (suite *BaselineVersionTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *BaselineVersionTestSuite) TestSaveBaselineStampsNewestMigration() {
+	err := t.migrator.Migrate(2)
+	t.NoError(err)
+
+	err = t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	file, err := os.Open(filepath.Join(t.migrationFolder, "baseline.sql"))
+	t.NoError(err)
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	t.True(scanner.Scan())
+	t.Equal("-- migrator:baseline 2024-01-02_10_00_00-roles.sql", scanner.Text())
+}
+
+func (t *BaselineVersionTestSuite) TestMigrateStartsAfterLoadedBaseline() {
+	err := t.migrator.Migrate(2)
+	t.NoError(err)
+
+	err = t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+	freshMigrator := newTestMigrator(freshDB, t.migrationFolder)
+
+	err = freshMigrator.LoadBaseline()
+	t.NoError(err)
+
+	statuses, err := freshMigrator.Status()
+	t.NoError(err)
+	t.Equal(migrator.StatusApplied, statuses[0].Status)
+	t.Equal(migrator.StatusApplied, statuses[1].Status)
+	t.Equal(migrator.StatusPending, statuses[2].Status)
+
+	err = freshMigrator.Migrate(0)
+	t.NoError(err)
+
+	tableCount, err := tableCountInDatabase(freshDB)
+	t.NoError(err)
+	t.Equal(5, tableCount)
+}
+
+func (t *BaselineVersionTestSuite) TestFreshDatabaseReplaysWithoutLoadingBaseline() {
+	err := t.migrator.Migrate(2)
+	t.NoError(err)
+
+	err = t.migrator.SaveBaseline()
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(freshDB, t.migrationFolder).Migrate(0)
+	t.NoError(err)
+
+	migrationCount, err := rowCountInTable(freshDB, "olb_migrations")
+	t.NoError(err)
+	t.Equal(3, migrationCount)
+}
+
+func (t *BaselineVersionTestSuite) TestBaselineAdoptsExistingDatabase() {
+	_, err := t.db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(255))")
+	t.NoError(err)
+	_, err = t.db.Exec("CREATE TABLE roles (id INTEGER PRIMARY KEY, name VARCHAR(255))")
+	t.NoError(err)
+
+	err = t.migrator.Baseline("2024-01-02_10_00_00-roles.sql")
+	t.NoError(err)
+
+	err = t.migrator.Migrate(0)
+	t.NoError(err)
+
+	rowCount, err := rowCountInTable(t.db, "posts")
+	t.NoError(err)
+	t.Equal(0, rowCount)
+
+	err = t.migrator.Baseline("2024-01-01_10_00_00-users.sql")
+	t.ErrorContains(err, "already baselined")
+}
+
+func (t *BaselineVersionTestSuite) TestBaselineRequiresExistingFile() {
+	err := t.migrator.Baseline("2024-01-09_10_00_00-missing.sql")
+	t.ErrorContains(err, "does not exist")
+}
@@ -21,7 +21,9 @@ func TestBaselineSuite(t *testing.T) {
 
 func (suite *baselineTestSuite) SetupTest() {
 	suite.db = initMemorySqlite()
-	suite.migrator = migrator.New(suite.db, "./test_fixtures_baseliner", tablePrefix)
+	var err error
+	suite.migrator, err = migrator.NewWithPath(suite.db, "./test_fixtures_baseliner", tablePrefix)
+	suite.Require().NoError(err)
 }
 
 func (suite *baselineTestSuite) TearDownTest() {
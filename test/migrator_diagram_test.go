@@ -0,0 +1,92 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type DiagramTestSuite struct {
+	suite.Suite
+	db       *sql.DB
+	migrator migrator.DBMigrator
+}
+
+func TestDiagramTestSuite(t *testing.T) {
+	suite.Run(t, new(DiagramTestSuite))
+}
+
+func (suite *DiagramTestSuite) SetupTest() {
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(suite.db, suite.T().TempDir())
+
+	statements := []string{
+		"CREATE TABLE roles (id INTEGER PRIMARY KEY, code VARCHAR(20) NOT NULL UNIQUE)",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, role_id INTEGER NOT NULL REFERENCES roles (id))",
+		"CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users (id), body)",
+	}
+	for _, statement := range statements {
+		_, err := suite.db.Exec(statement)
+		suite.NoError(err)
+	}
+
+	_, err := suite.migrator.Status()
+	suite.NoError(err)
+}
+
+func (suite *DiagramTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *DiagramTestSuite) TestMermaid() {
+	var diagram strings.Builder
+	err := t.migrator.GenerateERDiagram(&diagram, migrator.DiagramMermaid)
+	t.NoError(err)
+	t.Equal(`erDiagram
+    roles {
+        INTEGER id PK
+        VARCHAR(20) code UK
+    }
+    users {
+        INTEGER id PK
+        TEXT name
+        INTEGER role_id FK
+    }
+    posts {
+        INTEGER id PK
+        INTEGER user_id FK
+        ANY body
+    }
+    users }o--|| roles : "role_id"
+    posts }o--o| users : "user_id"
+`, diagram.String())
+}
+
+func (t *DiagramTestSuite) TestDOT() {
+	var diagram strings.Builder
+	err := t.migrator.GenerateERDiagram(&diagram, migrator.DiagramDOT)
+	t.NoError(err)
+
+	dot := diagram.String()
+	t.True(strings.HasPrefix(dot, "digraph schema {\n"))
+	t.Contains(dot, `<TR><TD PORT="code" ALIGN="LEFT">code VARCHAR(20) UK</TD></TR>`)
+	t.Contains(dot, `"users":"role_id" -> "roles":"id" [label="role_id"];`)
+	t.Contains(dot, `"posts":"user_id" -> "users":"id" [label="user_id", style=dashed];`)
+	t.NotContains(dot, "olb_migrations")
+}
+
+func (t *DiagramTestSuite) TestTableFilter() {
+	var diagram strings.Builder
+	err := t.migrator.GenerateERDiagram(&diagram, migrator.DiagramMermaid, migrator.ObjectFilter{Pattern: "[ru]*"})
+	t.NoError(err)
+	t.NotContains(diagram.String(), "posts")
+	t.Contains(diagram.String(), "users }o--|| roles")
+}
+
+func (t *DiagramTestSuite) TestUnknownFormat() {
+	err := t.migrator.GenerateERDiagram(&strings.Builder{}, "svg")
+	t.Error(err)
+}
@@ -0,0 +1,114 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type DriftTestSuite struct {
+	suite.Suite
+	db              *sql.DB
+	migrationFolder string
+	migrator        migrator.DBMigrator
+}
+
+func TestDriftTestSuite(t *testing.T) {
+	suite.Run(t, new(DriftTestSuite))
+}
+
+func (suite *DriftTestSuite) SetupTest() {
+	suite.migrationFolder = suite.T().TempDir()
+	err := copyFolder(testSquashFixtureFolder, suite.migrationFolder)
+	suite.NoError(err)
+
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(
+		suite.db,
+		suite.migrationFolder,
+		migrator.WithSchemaSnapshot(filepath.Join(suite.migrationFolder, "snapshot.sql")),
+	)
+}
+
+func (suite *DriftTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *DriftTestSuite) TestNoDriftAfterMigrate() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	drifts, err := t.migrator.DetectDrift()
+	t.NoError(err)
+	t.Empty(drifts)
+
+	err = t.migrator.Rollback(1)
+	t.NoError(err)
+
+	drifts, err = t.migrator.DetectDrift()
+	t.NoError(err)
+	t.Empty(drifts)
+}
+
+func (t *DriftTestSuite) TestHotFixDetected() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	_, err = t.db.Exec("ALTER TABLE users ADD COLUMN email VARCHAR(255)")
+	t.NoError(err)
+	_, err = t.db.Exec("CREATE INDEX idx_users_email ON users (email)")
+	t.NoError(err)
+	_, err = t.db.Exec("DROP TABLE roles")
+	t.NoError(err)
+
+	drifts, err := t.migrator.DetectDrift()
+	t.NoError(err)
+	t.Len(drifts, 3)
+
+	t.Equal(migrator.DriftAdded, drifts[0].Kind)
+	t.Equal("index", drifts[0].ObjectType)
+	t.Equal("idx_users_email", drifts[0].Name)
+	t.Equal("CREATE INDEX idx_users_email ON users (email)", drifts[0].Actual)
+
+	t.Equal(migrator.DriftRemoved, drifts[1].Kind)
+	t.Equal("table", drifts[1].ObjectType)
+	t.Equal("roles", drifts[1].Name)
+	t.Empty(drifts[1].Actual)
+
+	t.Equal(migrator.DriftChanged, drifts[2].Kind)
+	t.Equal("table", drifts[2].ObjectType)
+	t.Equal("users", drifts[2].Name)
+	t.Contains(drifts[2].Actual, "email VARCHAR(255)")
+	t.NotContains(drifts[2].Expected, "email")
+}
+
+func (t *DriftTestSuite) TestDriftAgainstBaseline() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	baselineMigrator := newTestMigrator(t.db, t.migrationFolder)
+	err = baselineMigrator.SaveBaseline()
+	t.NoError(err)
+
+	_, err = t.db.Exec("CREATE VIEW user_names AS SELECT name FROM users")
+	t.NoError(err)
+
+	drifts, err := baselineMigrator.DetectDrift()
+	t.NoError(err)
+	t.Equal([]migrator.SchemaDrift{
+		{
+			Kind:       migrator.DriftAdded,
+			ObjectType: "view",
+			Name:       "user_names",
+			Actual:     "CREATE VIEW user_names AS SELECT name FROM users",
+		},
+	}, drifts)
+}
+
+func (t *DriftTestSuite) TestBaselineWithoutAnnotationsRejected() {
+	_, err := newTestMigrator(t.db, "./test_fixtures_baseliner").DetectDrift()
+	t.ErrorContains(err, "has no object annotations")
+}
@@ -0,0 +1,120 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/marcboeker/go-duckdb"
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+const testDuckDBFixtureFolder = "./test_fixtures_duckdb"
+
+type DuckDBTestSuite struct {
+	suite.Suite
+	db       *sql.DB
+	migrator migrator.DBMigrator
+}
+
+func TestDuckDBRunner(t *testing.T) {
+	suite.Run(t, new(DuckDBTestSuite))
+}
+
+func (suite *DuckDBTestSuite) SetupTest() {
+	suite.db = initMemoryDuckDB()
+	suite.migrator = newTestMigrator(suite.db, testDuckDBFixtureFolder)
+}
+
+func (suite *DuckDBTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *DuckDBTestSuite) TestDuckDBMigrateAllTables() {
+	err := t.migrator.Migrate(0)
+	t.Nil(err)
+
+	tableCount, err := countInDuckDBCatalog(t.db, "duckdb_tables()")
+	t.Nil(err)
+
+	t.Equal(7, tableCount)
+
+	reportCount, err := rowCountInTable(t.db, tablePrefix+"_migration_reports")
+	t.Nil(err)
+
+	t.Equal(5, reportCount)
+}
+
+func (t *DuckDBTestSuite) TestDuckDBRollbackSpecificAmountOfTables() {
+	err := t.migrator.Migrate(0)
+	t.Nil(err)
+
+	err = t.migrator.Rollback(2)
+	t.Nil(err)
+
+	tableCount, err := countInDuckDBCatalog(t.db, "duckdb_tables()")
+	t.Nil(err)
+
+	t.Equal(5, tableCount)
+
+	err = t.migrator.Rollback(0)
+	t.Nil(err)
+
+	tableCount, err = countInDuckDBCatalog(t.db, "duckdb_tables()")
+	t.Nil(err)
+
+	t.Equal(2, tableCount)
+}
+
+func (t *DuckDBTestSuite) TestDuckDBChecksumValidator() {
+	err := t.migrator.Migrate(3)
+	t.Nil(err)
+
+	errors := t.migrator.ChecksumValidation()
+	t.Len(errors, 0)
+}
+
+func (t *DuckDBTestSuite) TestDuckDBBaselineCreatedAndRestored() {
+	m := newTestMigrator(t.db, "./test_fixtures_duckdb_baseliner")
+	err := m.LoadBaseline()
+	t.NoError(err)
+
+	sequenceCount, err := countInDuckDBCatalog(t.db, "duckdb_sequences()")
+	t.NoError(err)
+	t.Equal(1, sequenceCount)
+
+	tableCount, err := countInDuckDBCatalog(t.db, "duckdb_tables()")
+	t.NoError(err)
+	t.Equal(2, tableCount)
+
+	indexCount, err := countInDuckDBCatalog(t.db, "duckdb_indexes()")
+	t.NoError(err)
+	t.Equal(2, indexCount)
+
+	viewCount, err := countInDuckDBCatalog(t.db, "duckdb_views() WHERE NOT internal")
+	t.NoError(err)
+	t.Equal(1, viewCount)
+
+	// Save it back and load it into a fresh database, the restored structure should match
+	err = m.SaveBaseline("test-fixture-duckdb-baseliner-save")
+	t.NoError(err)
+
+	restoredDB := initMemoryDuckDB()
+	defer restoredDB.Close()
+
+	restored := newTestMigrator(restoredDB, "test-fixture-duckdb-baseliner-save")
+	err = restored.LoadBaseline()
+	t.NoError(err)
+
+	tableCount, err = countInDuckDBCatalog(restoredDB, "duckdb_tables()")
+	t.NoError(err)
+	t.Equal(2, tableCount)
+
+	indexCount, err = countInDuckDBCatalog(restoredDB, "duckdb_indexes()")
+	t.NoError(err)
+	t.Equal(2, indexCount)
+
+	viewCount, err = countInDuckDBCatalog(restoredDB, "duckdb_views() WHERE NOT internal")
+	t.NoError(err)
+	t.Equal(1, viewCount)
+}
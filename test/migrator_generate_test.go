@@ -0,0 +1,216 @@
+package migrator_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+var (
+	generateSourceSchema = []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(255))",
+		"CREATE TABLE legacy (id INTEGER PRIMARY KEY)",
+		"CREATE INDEX idx_users_name ON users (name)",
+		"CREATE VIEW user_names AS SELECT name FROM users",
+	}
+	generateTargetSchema = []string{
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR(255), email VARCHAR(255) NOT NULL DEFAULT '')",
+		"CREATE TABLE roles (id INTEGER PRIMARY KEY, name VARCHAR(255))",
+		"CREATE INDEX idx_users_name ON users (name)",
+		"CREATE INDEX idx_users_email ON users (email)",
+		"CREATE VIEW user_names AS SELECT name, email FROM users",
+	}
+)
+
+type GenerateTestSuite struct {
+	suite.Suite
+	sourceDB        *sql.DB
+	targetDB        *sql.DB
+	migrationFolder string
+	migrator        migrator.DBMigrator
+}
+
+func TestGenerateTestSuite(t *testing.T) {
+	suite.Run(t, new(GenerateTestSuite))
+}
+
+func (suite *GenerateTestSuite) SetupTest() {
+	suite.migrationFolder = suite.T().TempDir()
+	suite.sourceDB = initMemorySqlite()
+	suite.targetDB = initMemorySqlite()
+	suite.NoError(execStatements(suite.sourceDB, generateSourceSchema))
+	suite.NoError(execStatements(suite.targetDB, generateTargetSchema))
+
+	suite.migrator = newTestMigrator(suite.sourceDB, suite.migrationFolder)
+}
+
+func (suite *GenerateTestSuite) TearDownTest() {
+	suite.sourceDB.Close()
+	suite.targetDB.Close()
+}
+
+func (t *GenerateTestSuite) TestGeneratedMigrationAndRollback() {
+	err := t.migrator.GenerateMigration(t.sourceDB, t.targetDB, "sync")
+	t.NoError(err)
+
+	entries, err := os.ReadDir(t.migrationFolder)
+	t.NoError(err)
+	t.Len(entries, 2)
+	t.True(strings.HasSuffix(entries[0].Name(), "-sync-rollback.sql"))
+	t.True(strings.HasSuffix(entries[1].Name(), "-sync.sql"))
+
+	targetSchema, err := describeSqliteSchema(t.targetDB)
+	t.NoError(err)
+	sourceSchema, err := describeSqliteSchema(t.sourceDB)
+	t.NoError(err)
+
+	err = t.migrator.Migrate(0)
+	t.NoError(err)
+
+	migratedSchema, err := describeSqliteSchema(t.sourceDB)
+	t.NoError(err)
+	t.Equal(targetSchema, migratedSchema)
+
+	err = t.migrator.Rollback(0)
+	t.NoError(err)
+
+	rolledBackSchema, err := describeSqliteSchema(t.sourceDB)
+	t.NoError(err)
+	t.Equal(sourceSchema, rolledBackSchema)
+}
+
+func (t *GenerateTestSuite) TestDataKeptWhenTableRebuilt() {
+	_, err := t.sourceDB.Exec("INSERT INTO users (name) VALUES ('admin')")
+	t.NoError(err)
+
+	err = t.migrator.GenerateMigration(t.sourceDB, t.targetDB, "sync")
+	t.NoError(err)
+
+	err = t.migrator.Migrate(0)
+	t.NoError(err)
+
+	var name, email string
+	err = t.sourceDB.QueryRow("SELECT name, email FROM users").Scan(&name, &email)
+	t.NoError(err)
+	t.Equal("admin", name)
+	t.Equal("", email)
+}
+
+func (t *GenerateTestSuite) TestNoChanges() {
+	err := t.migrator.GenerateMigration(t.targetDB, t.targetDB, "sync")
+	t.ErrorIs(err, migrator.ErrNoSchemaChanges)
+
+	entries, err := os.ReadDir(t.migrationFolder)
+	t.NoError(err)
+	t.Empty(entries)
+}
+
+func (t *GenerateTestSuite) TestDifferentDialectsRejected() {
+	duckDB := initMemoryDuckDB()
+	defer duckDB.Close()
+
+	err := t.migrator.GenerateMigration(t.sourceDB, duckDB, "sync")
+	t.ErrorContains(err, "cannot generate migration")
+
+	entries, err := os.ReadDir(t.migrationFolder)
+	t.NoError(err)
+	t.Empty(entries)
+}
+
+func (t *GenerateTestSuite) TestUnknownDriverRejected() {
+	unknownDB := sql.OpenDB(unknownConnector{})
+	defer unknownDB.Close()
+
+	err := t.migrator.GenerateMigration(t.sourceDB, unknownDB, "sync")
+	t.ErrorContains(err, "cannot detect the database type")
+
+	entries, err := os.ReadDir(t.migrationFolder)
+	t.NoError(err)
+	t.Empty(entries)
+}
+
+// unknownConnector is a connector of a driver the migrator does not know, it never connects
+type unknownConnector struct{}
+
+func (unknownConnector) Connect(context.Context) (driver.Conn, error) {
+	return nil, errors.New("not connectable")
+}
+
+func (unknownConnector) Driver() driver.Driver {
+	return unknownDriver{}
+}
+
+type unknownDriver struct{}
+
+func (unknownDriver) Open(string) (driver.Conn, error) {
+	return nil, errors.New("not connectable")
+}
+
+func execStatements(db *sql.DB, statements []string) error {
+	for _, statement := range statements {
+		if _, err := db.Exec(statement); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// describeSqliteSchema lists the objects except the migration tables, with the columns of the tables
+func describeSqliteSchema(db *sql.DB) ([]string, error) {
+	rows, err := db.Query("SELECT type, name, sql FROM sqlite_master WHERE name NOT LIKE 'olb_%' AND sql IS NOT NULL ORDER BY type, name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var objects []string
+	var objectType, name, objectSQL string
+	for rows.Next() {
+		if err := rows.Scan(&objectType, &name, &objectSQL); err != nil {
+			return nil, err
+		}
+
+		if objectType != "table" {
+			objects = append(objects, objectType+" "+name+" "+objectSQL)
+			continue
+		}
+
+		objects = append(objects, objectType+" "+name)
+	}
+
+	tables := make([]string, 0)
+	for _, object := range objects {
+		if name, ok := strings.CutPrefix(object, "table "); ok {
+			tables = append(tables, name)
+		}
+	}
+
+	for _, table := range tables {
+		columnRows, err := db.Query(fmt.Sprintf("SELECT name, type, \"notnull\", COALESCE(dflt_value, ''), pk FROM pragma_table_info('%s')", table))
+		if err != nil {
+			return nil, err
+		}
+
+		for columnRows.Next() {
+			var columnName, columnType, columnDefault string
+			var notNull, pk int
+			if err := columnRows.Scan(&columnName, &columnType, &notNull, &columnDefault, &pk); err != nil {
+				columnRows.Close()
+				return nil, err
+			}
+			objects = append(objects, fmt.Sprintf("column %s.%s %s %d %s %d", table, columnName, columnType, notNull, columnDefault, pk))
+		}
+		columnRows.Close()
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,131 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type InspectTestSuite struct {
+	suite.Suite
+}
+
+func TestInspectTestSuite(t *testing.T) {
+	suite.Run(t, new(InspectTestSuite))
+}
+
+func (t *InspectTestSuite) createSchema(db *sql.DB, statements []string) migrator.DBMigrator {
+	for _, statement := range statements {
+		_, err := db.Exec(statement)
+		t.NoError(err)
+	}
+
+	return newTestMigrator(db, t.T().TempDir())
+}
+
+func (t *InspectTestSuite) TestSQLite() {
+	db := initMemorySqlite()
+	defer db.Close()
+
+	m := t.createSchema(db, []string{
+		"CREATE TABLE roles (id INTEGER PRIMARY KEY, code TEXT NOT NULL, tenant INTEGER DEFAULT 1, UNIQUE (tenant, code))",
+		"CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT, tenant INTEGER, role_code TEXT, FOREIGN KEY (tenant, role_code) REFERENCES roles (tenant, code))",
+		"CREATE INDEX idx_users_name ON users (name, tenant)",
+		"CREATE VIEW user_names AS SELECT name FROM users",
+		"CREATE TRIGGER users_insert AFTER INSERT ON users BEGIN SELECT 1; END",
+	})
+	_, err := m.Status()
+	t.NoError(err)
+
+	schema, err := m.Inspect()
+	t.NoError(err)
+	t.Len(schema.Tables, 2)
+
+	roles := schema.Tables[0]
+	t.Equal("roles", roles.Name)
+	t.Equal([]string{"id", "code", "tenant"}, columnNames(roles.Columns))
+	t.True(roles.Columns[0].PrimaryKey)
+	t.True(roles.Columns[1].NotNull)
+	t.Equal("1", roles.Columns[2].Default.String)
+	t.Equal([]migrator.Constraint{
+		{Type: migrator.ConstraintPrimaryKey, Columns: []string{"id"}},
+		{Type: migrator.ConstraintUnique, Columns: []string{"tenant", "code"}},
+	}, roles.Constraints)
+	t.Empty(roles.Indexes)
+
+	users := schema.Tables[1]
+	t.Equal("users", users.Name)
+	t.Contains(users.Constraints, migrator.Constraint{
+		Type:              migrator.ConstraintForeignKey,
+		Columns:           []string{"tenant", "role_code"},
+		ReferencedTable:   "roles",
+		ReferencedColumns: []string{"tenant", "code"},
+	})
+	t.Equal([]migrator.Index{{Name: "idx_users_name", Columns: []string{"name", "tenant"}}}, users.Indexes)
+
+	t.Len(schema.Views, 1)
+	t.Equal("user_names", schema.Views[0].Name)
+	t.Contains(schema.Views[0].SQL, "CREATE VIEW user_names")
+
+	t.Equal([]migrator.Trigger{{Name: "users_insert", Table: "users", SQL: "CREATE TRIGGER users_insert AFTER INSERT ON users BEGIN SELECT 1; END"}}, schema.Triggers)
+	t.Empty(schema.Routines)
+}
+
+func (t *InspectTestSuite) TestDuckDB() {
+	db := initMemoryDuckDB()
+	defer db.Close()
+
+	m := t.createSchema(db, []string{
+		"CREATE TABLE roles (id INTEGER PRIMARY KEY, code VARCHAR NOT NULL, tenant INTEGER DEFAULT 1, CHECK (tenant > 0), UNIQUE (tenant, code))",
+		"CREATE TABLE users (id INTEGER, name VARCHAR, tenant INTEGER, role_code VARCHAR, FOREIGN KEY (tenant, role_code) REFERENCES roles (tenant, code))",
+		"CREATE INDEX idx_users_name ON users (coalesce(name, role_code), tenant)",
+	})
+
+	schema, err := m.Inspect()
+	t.NoError(err)
+	t.Len(schema.Tables, 2)
+
+	roles := schema.Tables[0]
+	t.Equal("roles", roles.Name)
+	t.Equal([]string{"id", "code", "tenant"}, columnNames(roles.Columns))
+	t.True(roles.Columns[0].PrimaryKey)
+	t.True(roles.Columns[1].NotNull)
+	t.Len(roles.Constraints, 3)
+	t.Equal(migrator.ConstraintCheck, roles.Constraints[1].Type)
+	t.Equal("(tenant > 0)", roles.Constraints[1].Check)
+
+	users := schema.Tables[1]
+	t.Equal("users", users.Name)
+	t.Equal([]string{"tenant", "role_code"}, users.Constraints[0].Columns)
+	t.Equal("roles", users.Constraints[0].ReferencedTable)
+	t.Equal([]string{"tenant", "code"}, users.Constraints[0].ReferencedColumns)
+	t.Len(users.Indexes, 1)
+	t.Equal([]string{"(COALESCE(\"name\", role_code))", "tenant"}, users.Indexes[0].Columns)
+}
+
+func (t *InspectTestSuite) TestFilteredObjectsAreLeftOut() {
+	db := initMemorySqlite()
+	defer db.Close()
+
+	_, err := db.Exec("CREATE TABLE scratch_import (id INTEGER)")
+	t.NoError(err)
+
+	m := newTestMigrator(db, t.T().TempDir(), migrator.WithBaselineExclude(migrator.ObjectFilter{Pattern: "scratch_*"}))
+	_, err = m.Status()
+	t.NoError(err)
+
+	schema, err := m.Inspect()
+	t.NoError(err)
+	t.Empty(schema.Tables)
+}
+
+func columnNames(columns []migrator.Column) []string {
+	names := make([]string, len(columns))
+	for i, column := range columns {
+		names[i] = column.Name
+	}
+
+	return names
+}
@@ -29,8 +29,11 @@ func TestDbRunner(t *testing.T) {
 
 func (suite *DbTestSuite) SetupTest() {
 	suite.db = initMemorySqlite()
-	suite.migrator = migrator.New(suite.db, testFixtureFolder, tablePrefix)
-	suite.checksumMigrator = migrator.New(suite.db, testChecksumFixtureFolder, tablePrefix)
+	var err error
+	suite.migrator, err = migrator.NewWithPath(suite.db, testFixtureFolder, tablePrefix)
+	suite.Require().NoError(err)
+	suite.checksumMigrator, err = migrator.NewWithPath(suite.db, testChecksumFixtureFolder, tablePrefix)
+	suite.Require().NoError(err)
 }
 
 func (suite *DbTestSuite) TearDownTest() {
@@ -52,6 +55,21 @@ func (t *DbTestSuite) TestDBMigratorMigrateAllTables() {
 	t.Equal(5, reportCount)
 }
 
+func (t *DbTestSuite) TestDBBrokenTrackingTableIsNotEmpty() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	_, err = t.db.Exec("ALTER TABLE " + tablePrefix + "_migrations RENAME COLUMN created_at TO applied_at")
+	t.NoError(err)
+
+	err = t.migrator.Rollback(0)
+	t.ErrorContains(err, "created_at")
+
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(7, tableCount)
+}
+
 func (t *DbTestSuite) TestDBMigratorMigrateSpecifiedAmountOfTables() {
 	migrateCount := 2
 
@@ -205,3 +223,19 @@ func (t *DbTestSuite) TestDBChecksumValidator() {
 	errors := t.checksumMigrator.ChecksumValidation()
 	t.Len(errors, 0)
 }
+
+func (t *DbTestSuite) TestDBPostgresOptionsRejectedForOtherDrivers() {
+	_, err := migrator.New(
+		t.db,
+		migrator.WithMigrationPath(testFixtureFolder),
+		migrator.WithPostgresOptions(migrator.PostgresOptions{TrackingSchema: "migrator"}),
+	)
+	t.Error(err)
+
+	_, err = migrator.New(
+		t.db,
+		migrator.WithMigrationPath(testFixtureFolder),
+		migrator.WithPostgresOptions(migrator.PostgresOptions{BaselineSchemas: []string{"app", "audit"}}),
+	)
+	t.Error(err)
+}
@@ -0,0 +1,133 @@
+package migrator_test
+
+import (
+	"bytes"
+	"database/sql"
+	"log/slog"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+type OptionsTestSuite struct {
+	suite.Suite
+	db *sql.DB
+}
+
+func TestOptionsTestSuite(t *testing.T) {
+	suite.Run(t, new(OptionsTestSuite))
+}
+
+func (suite *OptionsTestSuite) SetupTest() {
+	suite.db = initMemorySqlite()
+}
+
+func (suite *OptionsTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *OptionsTestSuite) TestInvalidOptionsReturnError() {
+	_, err := migrator.New(nil)
+	t.Error(err)
+
+	_, err = migrator.New(t.db, migrator.WithMigrationPath(""))
+	t.Error(err)
+
+	_, err = migrator.New(t.db, migrator.WithTablePrefix("olb; DROP TABLE users"))
+	t.Error(err)
+
+	_, err = migrator.New(t.db, migrator.WithDialect("oracle"))
+	t.Error(err)
+
+	_, err = migrator.New(t.db, migrator.WithFS(nil))
+	t.Error(err)
+
+	_, err = migrator.New(t.db, migrator.WithLogger(nil))
+	t.Error(err)
+
+	_, err = migrator.New(t.db, migrator.WithClock(nil))
+	t.Error(err)
+
+	_, err = migrator.New(t.db, migrator.WithLockTimeout(-time.Second))
+	t.Error(err)
+
+	// SQLite has no advisory locks
+	_, err = migrator.New(t.db, migrator.WithLockTimeout(time.Second))
+	t.Error(err)
+}
+
+func (t *OptionsTestSuite) TestPathAndPrefixOptions() {
+	m, err := migrator.New(t.db, migrator.WithMigrationPath(testFixtureFolder), migrator.WithTablePrefix(tablePrefix))
+	t.NoError(err)
+
+	err = m.Migrate(0)
+	t.NoError(err)
+
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(7, tableCount)
+}
+
+func (t *OptionsTestSuite) TestPositionalWrapperReturnsError() {
+	m, err := migrator.NewWithPath(t.db, testFixtureFolder, "olb; DROP TABLE users")
+	t.ErrorContains(err, "invalid table prefix")
+	t.Nil(m)
+
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(0, tableCount)
+}
+
+func (t *OptionsTestSuite) TestMigrateFromFS() {
+	fsys := fstest.MapFS{
+		"migrations/2024-01-01_10_00_00-users.sql":          {Data: []byte("CREATE TABLE users (name TEXT);")},
+		"migrations/2024-01-01_10_00_00-users-rollback.sql": {Data: []byte("DROP TABLE users;")},
+		"migrations/2024-01-02_10_00_00-roles.sql":          {Data: []byte("CREATE TABLE roles (name TEXT);")},
+	}
+
+	m := newTestMigrator(t.db, "migrations", migrator.WithFS(fsys), migrator.WithDialect(migrator.DialectSQLite))
+
+	err := m.Migrate(0)
+	t.NoError(err)
+
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(4, tableCount)
+
+	t.Len(m.ChecksumValidation(), 0)
+
+	err = m.Rollback(0)
+	t.NoError(err)
+
+	tableCount, err = tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(3, tableCount)
+
+	err = m.AddNewMigrationFiles("not-allowed")
+	t.Error(err)
+}
+
+func (t *OptionsTestSuite) TestClockAndLogger() {
+	var logOutput bytes.Buffer
+	fixedTime := time.Date(2024, 5, 27, 19, 49, 38, 0, time.UTC)
+
+	m := newTestMigrator(
+		t.db,
+		testFixtureFolder,
+		migrator.WithClock(func() time.Time { return fixedTime }),
+		migrator.WithLogger(slog.New(slog.NewTextHandler(&logOutput, nil))),
+	)
+
+	err := m.Migrate(1)
+	t.NoError(err)
+
+	var createdAt time.Time
+	err = t.db.QueryRow("SELECT created_at FROM " + tablePrefix + "_migrations").Scan(&createdAt)
+	t.NoError(err)
+	t.Equal(fixedTime, createdAt)
+
+	t.Contains(logOutput.String(), "2023-07-27_17_57_47-fixture.sql")
+}
@@ -0,0 +1,123 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+const testRepeatableFixtureFolder = "./test_fixtures_repeatable"
+
+type RepeatableTestSuite struct {
+	suite.Suite
+	db       *sql.DB
+	migrator migrator.DBMigrator
+}
+
+func TestRepeatableTestSuite(t *testing.T) {
+	suite.Run(t, new(RepeatableTestSuite))
+}
+
+func (suite *RepeatableTestSuite) SetupTest() {
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(suite.db, testRepeatableFixtureFolder)
+}
+
+func (suite *RepeatableTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *RepeatableTestSuite) TestRepeatableRunsAfterVersioned() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	statuses, err := t.migrator.Status()
+	t.NoError(err)
+	t.Equal([]migrator.MigrationStatus{
+		{FileName: "2024-01-01_10_00_00-users.sql", Status: migrator.StatusApplied},
+		{FileName: "2024-01-02_10_00_00-roles.sql", Status: migrator.StatusApplied},
+		{FileName: "R__views.sql", Status: migrator.StatusApplied},
+	}, statuses)
+
+	viewCount, err := countInSqliteMasterForType(t.db, "view")
+	t.NoError(err)
+	t.Equal(1, viewCount)
+}
+
+func (t *RepeatableTestSuite) TestUnchangedRepeatableNotRerun() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	err = t.migrator.Migrate(0)
+	t.NoError(err)
+
+	migrationCount, err := rowCountInTable(t.db, "olb_migrations")
+	t.NoError(err)
+	t.Equal(3, migrationCount)
+}
+
+func (t *RepeatableTestSuite) TestChangedRepeatableRerun() {
+	migrationFolder := t.T().TempDir()
+	err := copyFolder(testRepeatableFixtureFolder, migrationFolder)
+	t.NoError(err)
+
+	repeatableMigrator := newTestMigrator(t.db, migrationFolder)
+	err = repeatableMigrator.Migrate(0)
+	t.NoError(err)
+
+	err = os.WriteFile(
+		filepath.Join(migrationFolder, "R__views.sql"),
+		[]byte("DROP VIEW IF EXISTS user_names;\n\nCREATE VIEW user_names AS SELECT id, name FROM users;\n"),
+		0o600,
+	)
+	t.NoError(err)
+
+	statuses, err := repeatableMigrator.Status()
+	t.NoError(err)
+	t.Equal(migrator.StatusPending, statuses[2].Status)
+
+	err = repeatableMigrator.Migrate(0)
+	t.NoError(err)
+
+	var columnCount int
+	err = t.db.QueryRow("SELECT COUNT(*) FROM pragma_table_info('user_names')").Scan(&columnCount)
+	t.NoError(err)
+	t.Equal(2, columnCount)
+
+	t.Empty(repeatableMigrator.ChecksumValidation())
+
+	report, err := repeatableMigrator.Report()
+	t.NoError(err)
+	t.Equal(2, strings.Count(report, "File Name: R__views.sql, Status: success"))
+}
+
+func (t *RepeatableTestSuite) TestRollbackSkipsRepeatable() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	err = t.migrator.Rollback(0)
+	t.NoError(err)
+
+	statuses, err := t.migrator.Status()
+	t.NoError(err)
+	t.Equal(migrator.StatusPending, statuses[0].Status)
+	t.Equal(migrator.StatusPending, statuses[1].Status)
+	t.Equal(migrator.StatusApplied, statuses[2].Status)
+}
+
+func (t *RepeatableTestSuite) TestRefreshReappliesRepeatable() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	err = t.migrator.Refresh()
+	t.NoError(err)
+
+	report, err := t.migrator.Report()
+	t.NoError(err)
+	t.Equal(2, strings.Count(report, "File Name: R__views.sql, Status: success"))
+}
@@ -20,7 +20,9 @@ func TestReportDbTestSuite(t *testing.T) {
 
 func (suite *ReportDbTestSuite) SetupTest() {
 	suite.db = initMemorySqlite()
-	suite.migrator = migrator.New(suite.db, testFixtureFolder, tablePrefix)
+	var err error
+	suite.migrator, err = migrator.NewWithPath(suite.db, testFixtureFolder, tablePrefix)
+	suite.Require().NoError(err)
 }
 
 func (suite *ReportDbTestSuite) TearDownTest() {
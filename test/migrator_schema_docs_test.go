@@ -0,0 +1,140 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type SchemaDocsTestSuite struct {
+	suite.Suite
+	db     *sql.DB
+	folder string
+}
+
+func TestSchemaDocsTestSuite(t *testing.T) {
+	suite.Run(t, new(SchemaDocsTestSuite))
+}
+
+func (suite *SchemaDocsTestSuite) SetupTest() {
+	suite.folder = suite.T().TempDir()
+}
+
+func (suite *SchemaDocsTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *SchemaDocsTestSuite) writeMigrations(migrations map[string]string) {
+	for fileName, content := range migrations {
+		err := os.WriteFile(filepath.Join(t.folder, fileName), []byte(content), 0644)
+		t.NoError(err)
+	}
+}
+
+func (t *SchemaDocsTestSuite) TestSqliteDocs() {
+	t.db = initMemorySqlite()
+	t.writeMigrations(map[string]string{
+		"2024-01-01_10_00_00-users.sql":            "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL DEFAULT 'guest');",
+		"2024-01-02_10_00_00-posts.sql":            "CREATE TABLE posts (id INTEGER PRIMARY KEY, user_id INTEGER REFERENCES users (id), body TEXT);",
+		"2024-01-03_10_00_00-index.sql":            "CREATE INDEX idx_posts_body ON posts (body);",
+		"2024-01-04_10_00_00-nickname.sql":         "ALTER TABLE users ADD COLUMN nickname TEXT;",
+		"2024-01-05_10_00_00-view.sql":             "CREATE VIEW user_names AS SELECT name FROM users;",
+		"2024-01-06_10_00_00-pending.sql":          "ALTER TABLE users ADD COLUMN age INTEGER;",
+		"2024-01-06_10_00_00-pending-rollback.sql": "",
+	})
+
+	m := newTestMigrator(t.db, t.folder)
+	err := m.Migrate(5)
+	t.NoError(err)
+
+	var docs strings.Builder
+	err = m.GenerateSchemaDocs(&docs)
+	t.NoError(err)
+	t.Equal(`# Database Schema
+
+## Tables
+
+### users
+
+Last changed by `+"`2024-01-05_10_00_00-view.sql`"+`
+
+| Column | Type | Nullable | Default | Comment |
+| --- | --- | --- | --- | --- |
+| id (PK) | INTEGER | NO |  |  |
+| name | TEXT | NO | 'guest' |  |
+| nickname | TEXT | YES |  |  |
+
+| Constraint | Type | Columns | Definition |
+| --- | --- | --- | --- |
+|  | PRIMARY KEY | id |  |
+
+### posts
+
+Last changed by `+"`2024-01-03_10_00_00-index.sql`"+`
+
+| Column | Type | Nullable | Default | Comment |
+| --- | --- | --- | --- | --- |
+| id (PK) | INTEGER | NO |  |  |
+| user_id | INTEGER | YES |  |  |
+| body | TEXT | YES |  |  |
+
+| Constraint | Type | Columns | Definition |
+| --- | --- | --- | --- |
+|  | FOREIGN KEY | user_id | REFERENCES users (id) |
+|  | PRIMARY KEY | id |  |
+
+| Index | Unique | Columns |
+| --- | --- | --- |
+| idx_posts_body | NO | body |
+
+## Views
+
+### user_names
+
+Last changed by `+"`2024-01-05_10_00_00-view.sql`"+`
+
+`+"```sql\nCREATE VIEW user_names AS SELECT name FROM users\n```\n", docs.String())
+}
+
+func (t *SchemaDocsTestSuite) TestDuckDBComments() {
+	t.db = initMemoryDuckDB()
+	t.writeMigrations(map[string]string{
+		"2024-01-01_10_00_00-users.sql": "CREATE TABLE users (id INTEGER PRIMARY KEY, name VARCHAR);\n" +
+			"COMMENT ON TABLE users IS 'Registered users';\n" +
+			"COMMENT ON COLUMN users.name IS 'Display name';",
+	})
+
+	m := newTestMigrator(t.db, t.folder)
+	err := m.Migrate(0)
+	t.NoError(err)
+
+	var docs strings.Builder
+	err = m.GenerateSchemaDocs(&docs)
+	t.NoError(err)
+	t.Contains(docs.String(), "### users\n\nRegistered users\n\nLast changed by `2024-01-01_10_00_00-users.sql`\n")
+	t.Contains(docs.String(), "| name | VARCHAR | YES |  | Display name |\n")
+	t.NotContains(docs.String(), "olb_migrations")
+}
+
+func (t *SchemaDocsTestSuite) TestDocsDoNotCreateMigrationTables() {
+	t.db = initMemorySqlite()
+	_, err := t.db.Exec("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+	t.NoError(err)
+	t.writeMigrations(map[string]string{
+		"2024-01-01_10_00_00-users.sql": "CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT);",
+	})
+
+	var docs strings.Builder
+	err = newTestMigrator(t.db, t.folder).GenerateSchemaDocs(&docs)
+	t.NoError(err)
+	t.Contains(docs.String(), "### users\n")
+	t.NotContains(docs.String(), "Last changed by")
+
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(1, tableCount)
+}
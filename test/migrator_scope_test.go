@@ -0,0 +1,80 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+const testScopedFixtureFolder = "./test_fixtures_scoped"
+
+type ScopeTestSuite struct {
+	suite.Suite
+	db       *sql.DB
+	migrator migrator.DBMigrator
+}
+
+func TestScopeTestSuite(t *testing.T) {
+	suite.Run(t, new(ScopeTestSuite))
+}
+
+func (suite *ScopeTestSuite) SetupTest() {
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(suite.db, testScopedFixtureFolder)
+}
+
+func (suite *ScopeTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *ScopeTestSuite) TestNonMatchingFilesSkipped() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(4, tableCount)
+
+	statuses, err := t.migrator.Status()
+	t.NoError(err)
+	t.Equal([]migrator.MigrationStatus{
+		{FileName: "2024-01-01_10_00_00-users.sql", Status: migrator.StatusApplied},
+		{FileName: "2024-01-02_10_00_00-dev-seed.sql", Status: migrator.StatusNotApplicable},
+		{FileName: "tag.fixtures/2024-01-03_10_00_00-fixtures.sql", Status: migrator.StatusNotApplicable},
+		{FileName: "2024-01-04_10_00_00-roles.sql", Status: migrator.StatusApplied},
+	}, statuses)
+}
+
+func (t *ScopeTestSuite) TestPendingInMatchingScope() {
+	statuses, err := t.migrator.Status(migrator.Scope{Environment: "local", Tags: []string{"fixtures"}})
+	t.NoError(err)
+
+	for _, status := range statuses {
+		t.Equal(migrator.StatusPending, status.Status)
+	}
+}
+
+func (t *ScopeTestSuite) TestRollbackSkipsNonMatchingFiles() {
+	err := t.migrator.Migrate(0, migrator.Scope{Environment: "development", Tags: []string{"fixtures"}})
+	t.NoError(err)
+
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(6, tableCount)
+
+	err = t.migrator.Rollback(0)
+	t.NoError(err)
+
+	tableCount, err = tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(4, tableCount)
+
+	err = t.migrator.Rollback(0, migrator.Scope{Environment: "development", Tags: []string{"fixtures"}})
+	t.NoError(err)
+
+	tableCount, err = tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(2, tableCount)
+}
@@ -0,0 +1,179 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+const testSeedFixtureFolder = "./test_fixtures_seeds"
+
+type SeedTestSuite struct {
+	suite.Suite
+	db       *sql.DB
+	migrator migrator.DBMigrator
+}
+
+func TestSeedTestSuite(t *testing.T) {
+	suite.Run(t, new(SeedTestSuite))
+}
+
+func (suite *SeedTestSuite) SetupTest() {
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(suite.db, testSeedFixtureFolder)
+
+	err := suite.migrator.Migrate(0)
+	suite.NoError(err)
+}
+
+func (suite *SeedTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *SeedTestSuite) TestSeedsAreNotMigrations() {
+	statuses, err := t.migrator.Status()
+	t.NoError(err)
+	t.Len(statuses, 1)
+
+	// users, settings, migrations and migration reports, the seed tables are created by the first seed only
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(4, tableCount)
+}
+
+func (t *SeedTestSuite) TestSeedRunsOnce() {
+	err := t.migrator.Seed()
+	t.NoError(err)
+
+	err = t.migrator.Seed()
+	t.NoError(err)
+
+	userCount, err := rowCountInTable(t.db, "users")
+	t.NoError(err)
+	t.Equal(1, userCount)
+
+	seedCount, err := rowCountInTable(t.db, "olb_seeds")
+	t.NoError(err)
+	t.Equal(2, seedCount)
+}
+
+func (t *SeedTestSuite) TestSeedInScope() {
+	err := t.migrator.Seed(migrator.Scope{Environment: "development"})
+	t.NoError(err)
+
+	userCount, err := rowCountInTable(t.db, "users")
+	t.NoError(err)
+	t.Equal(2, userCount)
+}
+
+func (t *SeedTestSuite) TestSeedFreshRunsAgain() {
+	err := t.migrator.Seed()
+	t.NoError(err)
+
+	err = t.migrator.SeedFresh()
+	t.NoError(err)
+
+	userCount, err := rowCountInTable(t.db, "users")
+	t.NoError(err)
+	t.Equal(2, userCount)
+
+	settingCount, err := rowCountInTable(t.db, "settings")
+	t.NoError(err)
+	t.Equal(1, settingCount)
+}
+
+func (t *SeedTestSuite) TestRerunOnChange() {
+	seedFolder := t.T().TempDir()
+	for _, fileName := range []string{"01-users.sql", "02-settings.sql"} {
+		err := copyFile(filepath.Join(testSeedFixtureFolder, "seeds", fileName), filepath.Join(seedFolder, fileName))
+		t.NoError(err)
+	}
+
+	seedMigrator := newTestMigrator(t.db, testSeedFixtureFolder, migrator.WithSeedPath(seedFolder))
+	err := seedMigrator.Seed()
+	t.NoError(err)
+
+	err = os.WriteFile(
+		filepath.Join(seedFolder, "01-users.sql"),
+		[]byte("INSERT INTO users (name) VALUES ('changed');\n"),
+		0o600,
+	)
+	t.NoError(err)
+
+	err = os.WriteFile(
+		filepath.Join(seedFolder, "02-settings.sql"),
+		[]byte("-- migrator:rerun-on-change\nDELETE FROM settings;\n\nINSERT INTO settings (name, value) VALUES ('theme', 'dark');\n"),
+		0o600,
+	)
+	t.NoError(err)
+
+	err = seedMigrator.Seed()
+	t.NoError(err)
+
+	userCount, err := rowCountInTable(t.db, "users")
+	t.NoError(err)
+	t.Equal(1, userCount)
+
+	var theme string
+	err = t.db.QueryRow("SELECT value FROM settings WHERE name = 'theme'").Scan(&theme)
+	t.NoError(err)
+	t.Equal("dark", theme)
+}
+
+func (t *SeedTestSuite) TestSeedNamesLikeMigrationFiles() {
+	seedFolder := t.T().TempDir()
+	seeds := map[string]string{
+		"01-baseline-users.sql": "INSERT INTO users (name) VALUES ('baseline');\n",
+		"02-users-rollback.sql": "INSERT INTO users (name) VALUES ('rollback');\n",
+		"03-notes.txt":          "INSERT INTO users (name) VALUES ('not a seed');\n",
+	}
+	for fileName, content := range seeds {
+		err := os.WriteFile(filepath.Join(seedFolder, fileName), []byte(content), 0o600)
+		t.NoError(err)
+	}
+
+	err := newTestMigrator(t.db, testSeedFixtureFolder, migrator.WithSeedPath(seedFolder)).Seed()
+	t.NoError(err)
+
+	userCount, err := rowCountInTable(t.db, "users")
+	t.NoError(err)
+	t.Equal(2, userCount)
+}
+
+func (t *SeedTestSuite) TestSeedReport() {
+	report, err := t.migrator.Report()
+	t.NoError(err)
+	t.NotContains(report, "Seeds:")
+
+	err = t.migrator.Seed()
+	t.NoError(err)
+
+	report, err = t.migrator.Report()
+	t.NoError(err)
+	t.Contains(report, "Seeds:\n")
+	t.Contains(report, "File Name: 01-users.sql, Status: success")
+	t.Contains(report, "File Name: 02-settings.sql, Status: success")
+}
+
+func (t *SeedTestSuite) TestMissingDefaultSeedFolderHasNoSeeds() {
+	m := newTestMigrator(t.db, t.T().TempDir())
+
+	err := m.Seed()
+	t.NoError(err)
+
+	report, err := m.Report()
+	t.NoError(err)
+	t.NotContains(report, "Seeds:")
+}
+
+func (t *SeedTestSuite) TestMissingSeedPathIsReported() {
+	seedPath := filepath.Join(t.T().TempDir(), "missing")
+	m := newTestMigrator(t.db, testSeedFixtureFolder, migrator.WithSeedPath(seedPath))
+
+	err := m.Seed()
+	t.ErrorContains(err, seedPath)
+}
@@ -0,0 +1,132 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+const testSourcesFixtureFolder = "./test_fixtures_sources"
+
+type SourcesTestSuite struct {
+	suite.Suite
+	db       *sql.DB
+	migrator migrator.DBMigrator
+}
+
+func TestSourcesTestSuite(t *testing.T) {
+	suite.Run(t, new(SourcesTestSuite))
+}
+
+func (suite *SourcesTestSuite) SetupTest() {
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(
+		suite.db,
+		testSourcesFixtureFolder+"/core",
+		migrator.WithSource("plugin", testSourcesFixtureFolder+"/plugin"),
+	)
+}
+
+func (suite *SourcesTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *SourcesTestSuite) TestSourcesMergedIntoOneTimeline() {
+	err := t.migrator.Migrate(2)
+	t.NoError(err)
+
+	rows, err := t.db.Query(fmt.Sprintf("SELECT file_name, source FROM %s_migrations ORDER BY file_name", tablePrefix))
+	t.NoError(err)
+	defer rows.Close()
+
+	migrated := make(map[string]string)
+	for rows.Next() {
+		var fileName, source string
+		t.NoError(rows.Scan(&fileName, &source))
+		migrated[fileName] = source
+	}
+
+	t.Equal(map[string]string{
+		"2024-01-01_10_00_00-core-users.sql":   "",
+		"2024-01-02_10_00_00-plugin-items.sql": "plugin",
+	}, migrated)
+}
+
+func (t *SourcesTestSuite) TestRollbackResolvesFileInSource() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(5, tableCount)
+
+	err = t.migrator.Rollback(0)
+	t.NoError(err)
+
+	tableCount, err = tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(2, tableCount)
+
+	t.Len(t.migrator.ChecksumValidation(), 0)
+}
+
+func (t *SourcesTestSuite) TestDuplicateFileAcrossSources() {
+	m := newTestMigrator(
+		t.db,
+		testSourcesFixtureFolder+"/core",
+		migrator.WithSource("duplicate", testSourcesFixtureFolder+"/duplicate"),
+	)
+
+	err := m.Migrate(0)
+	t.ErrorContains(err, "duplicate migration file 2024-01-01_10_00_00-core-users.sql")
+}
+
+func (t *SourcesTestSuite) TestDuplicateFileInScopeFolder() {
+	folder := t.T().TempDir()
+	err := os.MkdirAll(filepath.Join(folder, "env.development"), 0755)
+	t.NoError(err)
+	err = os.WriteFile(filepath.Join(folder, "env.development", "2024-01-01_10_00_00-core-users.sql"), []byte("SELECT 1;"), 0644)
+	t.NoError(err)
+
+	m := newTestMigrator(
+		t.db,
+		testSourcesFixtureFolder+"/core",
+		migrator.WithSource("duplicate", folder),
+	)
+
+	err = m.Migrate(0)
+	t.ErrorContains(err, "duplicate migration file 2024-01-01_10_00_00-core-users.sql")
+}
+
+func (t *SourcesTestSuite) TestInvalidSources() {
+	_, err := migrator.New(t.db, migrator.WithSource("", testSourcesFixtureFolder+"/plugin"))
+	t.Error(err)
+
+	_, err = migrator.New(
+		t.db,
+		migrator.WithSource("plugin", testSourcesFixtureFolder+"/plugin"),
+		migrator.WithSource("plugin", testSourcesFixtureFolder+"/duplicate"),
+	)
+	t.Error(err)
+}
+
+func (t *SourcesTestSuite) TestSourceColumnAddedToExistingMigrationTable() {
+	_, err := t.db.Exec(fmt.Sprintf(`CREATE TABLE %s_migrations (
+		file_name VARCHAR(255),
+		created_at DATETIME,
+		deleted_at DATETIME,
+		checksum CHAR(32))`, tablePrefix))
+	t.NoError(err)
+
+	err = t.migrator.Migrate(0)
+	t.NoError(err)
+
+	count, err := rowCountInTable(t.db, tablePrefix+"_migrations WHERE source = 'plugin'")
+	t.NoError(err)
+	t.Equal(1, count)
+}
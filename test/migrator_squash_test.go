@@ -0,0 +1,142 @@
+package migrator_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	migrator "github.com/olbrichattila/godbmigrator"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testSquashFixtureFolder = "./test_fixtures_squash"
+	testSquashUpTo          = "2024-01-02_10_00_00-roles.sql"
+)
+
+type SquashTestSuite struct {
+	suite.Suite
+	db              *sql.DB
+	migrationFolder string
+	migrator        migrator.DBMigrator
+}
+
+func TestSquashTestSuite(t *testing.T) {
+	suite.Run(t, new(SquashTestSuite))
+}
+
+func (suite *SquashTestSuite) SetupTest() {
+	suite.migrationFolder = suite.T().TempDir()
+	err := copyFolder(testSquashFixtureFolder, suite.migrationFolder)
+	suite.NoError(err)
+
+	suite.db = initMemorySqlite()
+	suite.migrator = newTestMigrator(suite.db, suite.migrationFolder)
+}
+
+func (suite *SquashTestSuite) TearDownTest() {
+	suite.db.Close()
+}
+
+func (t *SquashTestSuite) TestSquashArchivesFilesAndSavesBaseline() {
+	err := t.migrator.Migrate(2)
+	t.NoError(err)
+
+	err = t.migrator.Squash(testSquashUpTo)
+	t.NoError(err)
+
+	t.FileExists(filepath.Join(t.migrationFolder, "baseline.sql"))
+	t.FileExists(filepath.Join(t.migrationFolder, "squashed", "2024-01-01_10_00_00-users.sql"))
+	t.FileExists(filepath.Join(t.migrationFolder, "squashed", "2024-01-02_10_00_00-roles-rollback.sql"))
+	t.NoFileExists(filepath.Join(t.migrationFolder, testSquashUpTo))
+
+	statuses, err := t.migrator.Status()
+	t.NoError(err)
+	t.Equal([]migrator.MigrationStatus{
+		{FileName: "2024-01-03_10_00_00-posts.sql", Status: migrator.StatusPending},
+	}, statuses)
+
+	err = t.migrator.Migrate(0)
+	t.NoError(err)
+	t.Empty(t.migrator.ChecksumValidation())
+
+	tableCount, err := tableCountInDatabase(t.db)
+	t.NoError(err)
+	t.Equal(5, tableCount)
+}
+
+func (t *SquashTestSuite) TestSquashRefusedWhenDatabaseIsPastPoint() {
+	err := t.migrator.Migrate(0)
+	t.NoError(err)
+
+	err = t.migrator.Squash(testSquashUpTo)
+	t.ErrorContains(err, "the database is past")
+	t.NoFileExists(filepath.Join(t.migrationFolder, "baseline.sql"))
+}
+
+func (t *SquashTestSuite) TestSquashRefusedWhenNotApplied() {
+	err := t.migrator.Migrate(1)
+	t.NoError(err)
+
+	err = t.migrator.Squash(testSquashUpTo)
+	t.ErrorContains(err, "is not applied")
+}
+
+func (t *SquashTestSuite) TestFreshDatabaseLoadsSquashedBaseline() {
+	err := t.migrator.Migrate(2)
+	t.NoError(err)
+
+	err = t.migrator.Squash(testSquashUpTo)
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+	freshMigrator := newTestMigrator(freshDB, t.migrationFolder)
+
+	err = freshMigrator.LoadBaseline()
+	t.NoError(err)
+
+	err = freshMigrator.Migrate(0)
+	t.NoError(err)
+
+	rowCount, err := rowCountInTable(freshDB, "posts")
+	t.NoError(err)
+	t.Equal(0, rowCount)
+}
+
+func (t *SquashTestSuite) TestMigrateRefusedBeforeBaselineLoaded() {
+	err := t.migrator.Migrate(2)
+	t.NoError(err)
+
+	err = t.migrator.Squash(testSquashUpTo)
+	t.NoError(err)
+
+	freshDB := initMemorySqlite()
+	defer freshDB.Close()
+
+	err = newTestMigrator(freshDB, t.migrationFolder).Migrate(0)
+	t.ErrorContains(err, "run LoadBaseline first")
+}
+
+func (t *SquashTestSuite) TestExistingDatabaseSwitchesToBaseline() {
+	existingDB := initMemorySqlite()
+	defer existingDB.Close()
+	existingMigrator := newTestMigrator(existingDB, t.migrationFolder)
+
+	err := existingMigrator.Migrate(2)
+	t.NoError(err)
+
+	err = t.migrator.Migrate(2)
+	t.NoError(err)
+
+	err = t.migrator.Squash(testSquashUpTo)
+	t.NoError(err)
+
+	err = existingMigrator.Migrate(0)
+	t.NoError(err)
+	t.Empty(existingMigrator.ChecksumValidation())
+
+	rowCount, err := rowCountInTable(existingDB, "posts")
+	t.NoError(err)
+	t.Equal(0, rowCount)
+}
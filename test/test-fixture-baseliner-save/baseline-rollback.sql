@@ -0,0 +1,34 @@
+-- migrator:object trigger check_loan_dates
+DROP TRIGGER IF EXISTS "check_loan_dates";
+-- migrator:object view book_authors
+DROP VIEW IF EXISTS "book_authors";
+-- migrator:object index idx_book_title
+DROP INDEX IF EXISTS "idx_book_title";
+-- migrator:object index idx_book_author_title
+DROP INDEX IF EXISTS "idx_book_author_title";
+-- migrator:object table users6
+DROP TABLE IF EXISTS "users6";
+-- migrator:object table users5
+DROP TABLE IF EXISTS "users5";
+-- migrator:object table users4
+DROP TABLE IF EXISTS "users4";
+-- migrator:object table users3
+DROP TABLE IF EXISTS "users3";
+-- migrator:object table users2
+DROP TABLE IF EXISTS "users2";
+-- migrator:object table users
+DROP TABLE IF EXISTS "users";
+-- migrator:object table migrations
+DROP TABLE IF EXISTS "migrations";
+-- migrator:object table migration_reports
+DROP TABLE IF EXISTS "migration_reports";
+-- migrator:object table loans
+DROP TABLE IF EXISTS "loans";
+-- migrator:object table members
+DROP TABLE IF EXISTS "members";
+-- migrator:object table books
+DROP TABLE IF EXISTS "books";
+-- migrator:object table genres
+DROP TABLE IF EXISTS "genres";
+-- migrator:object table authors
+DROP TABLE IF EXISTS "authors";
@@ -0,0 +1,12 @@
+-- migrator:object view book_authors
+DROP VIEW IF EXISTS "book_authors";
+-- migrator:object index idx_book_title
+DROP INDEX IF EXISTS "idx_book_title";
+-- migrator:object index idx_book_author_title
+DROP INDEX IF EXISTS "idx_book_author_title";
+-- migrator:object table books
+DROP TABLE IF EXISTS "books";
+-- migrator:object table authors
+DROP TABLE IF EXISTS "authors";
+-- migrator:object sequence seq_author_id
+DROP SEQUENCE IF EXISTS "seq_author_id";
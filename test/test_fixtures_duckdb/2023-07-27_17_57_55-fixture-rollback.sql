@@ -0,0 +1,2 @@
+DROP VIEW t4_names;
+DROP TABLE t4
@@ -0,0 +1,12 @@
+-- Comment
+CREATE TABLE t4 (
+    -- in SQL body comment
+    name TEXT
+);
+
+-- Another comment
+-- Multiple comments
+/* Other type of comment */
+
+CREATE VIEW t4_names AS
+SELECT name FROM t4;
@@ -0,0 +1,6 @@
+-- This is  a test comment
+CREATE TABLE t5 (name TEXT);
+-- This is another test comment
+DROP TABLE t5;
+/*  This is a different type of comment */
+CREATE TABLE t5 (name TEXT);
@@ -0,0 +1,19 @@
+CREATE SEQUENCE seq_author_id START 1;
+CREATE TABLE authors (
+    author_id INTEGER PRIMARY KEY DEFAULT nextval('seq_author_id'),
+    author_name VARCHAR NOT NULL
+);
+CREATE TABLE books (
+    book_id INTEGER PRIMARY KEY,
+    title VARCHAR NOT NULL,
+    author_id INTEGER,
+    FOREIGN KEY (author_id) REFERENCES authors(author_id)
+);
+CREATE INDEX idx_book_title ON books (title);
+CREATE INDEX idx_book_author_title ON books (author_id, title);
+DELIMITER ;
+CREATE VIEW book_authors AS
+SELECT b.title, a.author_name
+FROM books AS b
+JOIN authors AS a ON b.author_id = a.author_id
+DELIMITER ;;
@@ -0,0 +1 @@
+DROP TABLE dev_seed;
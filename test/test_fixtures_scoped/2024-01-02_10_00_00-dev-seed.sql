@@ -0,0 +1,3 @@
+-- Developer seed data
+-- migrator:env development, local
+CREATE TABLE dev_seed (name TEXT);
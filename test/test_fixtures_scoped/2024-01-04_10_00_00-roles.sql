@@ -0,0 +1 @@
+CREATE TABLE roles (name TEXT);
@@ -0,0 +1 @@
+CREATE TABLE fixtures (name TEXT);
@@ -0,0 +1,9 @@
+CREATE TABLE users (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(255)
+);
+
+CREATE TABLE settings (
+    name VARCHAR(255) PRIMARY KEY,
+    value VARCHAR(255)
+);
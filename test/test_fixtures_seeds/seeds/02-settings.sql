@@ -0,0 +1,4 @@
+-- migrator:rerun-on-change
+DELETE FROM settings;
+
+INSERT INTO settings (name, value) VALUES ('theme', 'light');
@@ -0,0 +1,2 @@
+-- migrator:env development
+INSERT INTO users (name) VALUES ('demo');
@@ -0,0 +1 @@
+CREATE TABLE core_roles (name TEXT);
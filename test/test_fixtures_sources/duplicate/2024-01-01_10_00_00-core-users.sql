@@ -0,0 +1 @@
+CREATE TABLE core_users (name TEXT);
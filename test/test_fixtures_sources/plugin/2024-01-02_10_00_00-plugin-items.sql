@@ -0,0 +1 @@
+CREATE TABLE plugin_items (name TEXT);
@@ -0,0 +1,4 @@
+CREATE TABLE roles (
+    id INTEGER PRIMARY KEY,
+    name VARCHAR(255)
+);